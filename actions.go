@@ -0,0 +1,109 @@
+package main
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//-----------------------------------------------------------------[ ACTIONS ]--
+
+// appAccels lists the keyboard accelerators of the application actions.
+var appAccels = map[string][]string{
+	"win.fullscreen":  {"F11"},
+	"app.quit":        {"<Control>q"},
+	"app.about":       {"F1"},
+	"app.shortcuts":   {"<Control>question"},
+	"app.preferences": {"<Control>comma"},
+}
+
+// installActions adds the window and application actions, shared by the
+// gallery menus, and their accelerators.
+func installActions() {
+	isMaximized := gapp.Win.IsMaximized()
+	vMax := glib.NewVariantBoolean(isMaximized)
+	actFullScreen := gio.NewSimpleActionStateful("fullscreen", nil, vMax)
+	actFullScreen.Connect("change-state", func() { // Args: *gio.SimpleAction, *glib.Variant  (the variant crash ATM)
+		events.Log("Actions", "change-state", "win.fullscreen", vMax.Boolean())
+		newval := !gapp.Win.IsMaximized()
+		if newval {
+			gapp.Win.Maximize()
+		} else {
+			gapp.Win.Unmaximize()
+		}
+		vMax = glib.NewVariantBoolean(newval)
+		actFullScreen.SetState(vMax)
+	})
+	gapp.Win.AddAction(actFullScreen)
+
+	for name, call := range map[string]func(){
+		"quit":        gapp.App.Quit,
+		"about":       showAbout,
+		"shortcuts":   showShortcuts,
+		"preferences": showPreferences,
+	} {
+		name, call := name, call // We're in a loop, so we need to make a static copy for the callback.
+		act := gio.NewSimpleAction(name, nil)
+		act.Connect("activate", func() {
+			events.Log("Actions", "activate", "app."+name)
+			call()
+		})
+		gapp.App.AddAction(act)
+	}
+
+	for action, accels := range appAccels {
+		gapp.App.SetAccelsForAction(action, accels)
+	}
+}
+
+// appMenuSections returns the sections of the application menu, used by
+// all gallery menus: a single action model drives them.
+func appMenuSections() (view, app, quit *gio.Menu) {
+	view = gio.NewMenu()
+	view.Append("FullScreen", "win.fullscreen")
+
+	app = gio.NewMenu()
+	app.Append("Preferences", "app.preferences")
+	app.Append("Keyboard Shortcuts", "app.shortcuts")
+	app.Append("About", "app.about")
+
+	quit = gio.NewMenu()
+	quit.Append("Quit", "app.quit")
+	return view, app, quit
+}
+
+// showPreferences shows a small preferences window, editing GTK settings.
+// Also used by the app.preferences action.
+func showPreferences() {
+	settings := gtk.SettingsGetDefault()
+	grid := gtk.NewGrid()
+	grid.SetColumnSpacing(boxMargin * 2)
+	grid.SetRowSpacing(boxMargin)
+	for i, row := range []struct{ label, property string }{
+		{"Dark theme", "gtk-application-prefer-dark-theme"},
+		{"Animations", "gtk-enable-animations"},
+		{"Cursor blink", "gtk-cursor-blink"},
+	} {
+		row := row // We're in a loop, so we need to make a static copy for the callback.
+		sw := gtk.NewSwitch()
+		sw.SetActive(settings.ObjectProperty(row.property) == true)
+		sw.Connect("notify::active", func() {
+			settings.SetObjectProperty(row.property, sw.Active())
+			events.Log("Preferences", "notify::active", row.property, sw.Active())
+		})
+		label := gtk.NewLabel(row.label)
+		label.SetHAlign(gtk.AlignStart)
+		label.SetHExpand(true)
+		grid.Attach(label, 0, i, 1, 1)
+		grid.Attach(sw, 1, i, 1, 1)
+	}
+
+	w := gtk.NewWindow()
+	w.SetTitle("Preferences")
+	w.SetTransientFor(&gapp.Win.Window)
+	w.SetChild(gtknew.VBox(boxMargin, grid))
+	w.Show()
+}
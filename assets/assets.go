@@ -0,0 +1,13 @@
+// Package assets loads the gallery images, from the bundled files or remote
+// sources with a disk cache.
+//
+// Loads can run in background goroutines, report their progress, and be
+// canceled or limited in time with a context.
+package assets
+
+import "embed"
+
+// Bundled holds the images shipped with the gallery binary, by key.
+//
+//go:embed *.png
+var Bundled embed.FS
@@ -0,0 +1,265 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//
+//------------------------------------------------------------------[ ERRORS ]--
+
+// ErrUnknownKey is returned when no source is defined for the asset key.
+var ErrUnknownKey = errors.New("assets: unknown key")
+
+// HTTPError is returned when the server answered with an unexpected status.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// FetchError is returned when a remote asset couldn't be fetched.
+// Err can be an *HTTPError, context.Canceled, context.DeadlineExceeded or a
+// network error.
+type FetchError struct {
+	Key string
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string { return fmt.Sprintf("assets: fetch %s: %s", e.Key, e.Err) }
+
+// Unwrap returns the underlying error.
+func (e *FetchError) Unwrap() error { return e.Err }
+
+//
+//------------------------------------------------------------------[ LOADER ]--
+
+// Progress reports the download state of an asset.
+type Progress struct {
+	Key   string
+	Done  int64 // Bytes received.
+	Total int64 // Expected size, or -1 when unknown.
+}
+
+// Fraction returns the progress between 0 and 1, or -1 when unknown.
+func (p Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.Done) / float64(p.Total)
+}
+
+// Loader loads assets by key, from the bundled files or their remote source.
+//
+// Remote assets are cached on disk and revalidated with their ETag. When the
+// remote source fails, the cached then the bundled version are used.
+type Loader struct {
+	Sources  map[string]string // Remote URL by asset key.
+	Bundled  fs.FS             // Files by asset key, used offline. Can be nil.
+	Remote   bool              // Fetch remote sources. Bundled files only when false.
+	CacheDir string            // Disk cache directory. Empty disables the cache.
+	Client   *http.Client      // Defaults to http.DefaultClient.
+	Timeout  time.Duration     // Limits each remote fetch. Zero means no limit.
+
+	mu   sync.Mutex
+	data map[string][]byte // Memory cache of loaded assets.
+}
+
+// NewLoader creates a loader for the sources, using the bundled files.
+func NewLoader(sources map[string]string) *Loader {
+	return &Loader{
+		Sources: sources,
+		Bundled: Bundled,
+	}
+}
+
+// DefaultCacheDir returns the gallery cache directory ($XDG_CACHE_HOME/name).
+func DefaultCacheDir(name string) string {
+	dir, e := os.UserCacheDir()
+	if e != nil {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
+
+// Get returns an asset if it was already loaded.
+func (l *Loader) Get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	byts, ok := l.data[key]
+	return byts, ok
+}
+
+// LoadAsync loads an asset in a goroutine. Callbacks are called from that
+// goroutine, use gtknew.Idle to update widgets.
+//
+// progress can be nil.
+func (l *Loader) LoadAsync(ctx context.Context, key string, progress func(Progress), done func([]byte, error)) {
+	go func() { done(l.Load(ctx, key, progress)) }()
+}
+
+// Load returns an asset, blocking until it's loaded.
+//
+// When a remote fetch failed but a fallback was found, both the data and a
+// *FetchError are returned.
+func (l *Loader) Load(ctx context.Context, key string, progress func(Progress)) ([]byte, error) {
+	if byts, ok := l.Get(key); ok {
+		return byts, nil
+	}
+
+	url, ok := l.Sources[key]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	var errFetch error
+	if l.Remote {
+		byts, e := l.fetch(ctx, key, url, progress)
+		if e == nil {
+			l.store(key, byts)
+			return byts, nil
+		}
+		errFetch = &FetchError{Key: key, URL: url, Err: e}
+
+		if byts, _, e := l.readCache(key); e == nil {
+			return byts, errFetch // Not stored, so the next load retries.
+		}
+	}
+
+	if l.Bundled != nil {
+		if byts, e := fs.ReadFile(l.Bundled, key); e == nil {
+			if errFetch == nil {
+				l.store(key, byts)
+			}
+			return byts, errFetch
+		}
+	}
+
+	if errFetch != nil {
+		return nil, errFetch
+	}
+	return nil, fmt.Errorf("assets: %s: %w", key, fs.ErrNotExist)
+}
+
+func (l *Loader) store(key string, byts []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.data == nil {
+		l.data = make(map[string][]byte)
+	}
+	l.data[key] = byts
+}
+
+// fetch downloads the asset, revalidating the cached version if any.
+func (l *Loader) fetch(ctx context.Context, key, url string, progress func(Progress)) ([]byte, error) {
+	if l.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Timeout)
+		defer cancel()
+	}
+
+	req, e := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if e != nil {
+		return nil, e
+	}
+	cached, etag, errCache := l.readCache(key)
+	if errCache == nil && etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, e := client.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && errCache == nil:
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, &HTTPError{URL: url, StatusCode: resp.StatusCode}
+	}
+
+	var buf bytes.Buffer
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{r: resp.Body, call: progress, p: Progress{Key: key, Total: resp.ContentLength}}
+	}
+	if _, e := io.Copy(&buf, body); e != nil {
+		return nil, e
+	}
+
+	l.writeCache(key, buf.Bytes(), resp.Header.Get("ETag"))
+	return buf.Bytes(), nil
+}
+
+//
+//-------------------------------------------------------------------[ CACHE ]--
+
+func (l *Loader) cachePath(key string) string {
+	return filepath.Join(l.CacheDir, filepath.Base(key))
+}
+
+// readCache returns the cached asset and its ETag.
+func (l *Loader) readCache(key string) ([]byte, string, error) {
+	if l.CacheDir == "" {
+		return nil, "", fs.ErrNotExist
+	}
+	byts, e := os.ReadFile(l.cachePath(key))
+	if e != nil {
+		return nil, "", e
+	}
+	etag, _ := os.ReadFile(l.cachePath(key) + ".etag")
+	return byts, string(etag), nil
+}
+
+// writeCache saves the asset and its ETag. Errors are ignored, the cache is
+// only an optimization.
+func (l *Loader) writeCache(key string, byts []byte, etag string) {
+	if l.CacheDir == "" || os.MkdirAll(l.CacheDir, 0o755) != nil {
+		return
+	}
+	if os.WriteFile(l.cachePath(key), byts, 0o644) != nil {
+		return
+	}
+	if etag == "" {
+		os.Remove(l.cachePath(key) + ".etag")
+		return
+	}
+	os.WriteFile(l.cachePath(key)+".etag", []byte(etag), 0o644)
+}
+
+//
+//---------------------------------------------------------[ PROGRESS READER ]--
+
+type progressReader struct {
+	r    io.Reader
+	call func(Progress)
+	p    Progress
+}
+
+func (pr *progressReader) Read(data []byte) (int, error) {
+	n, e := pr.r.Read(data)
+	if n > 0 {
+		pr.p.Done += int64(n)
+		pr.call(pr.p)
+	}
+	return n, e
+}
@@ -0,0 +1,173 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+const testETag = `"v1"`
+
+var testImage = []byte("remote image data")
+
+// newTestServer serves the test image with an ETag, answering 304 to matching
+// revalidations. It counts the requests and their 304 answers.
+func newTestServer(t *testing.T) (srv *httptest.Server, requests, notModified *int32) {
+	requests, notModified = new(int32), new(int32)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch r.URL.Path {
+		case "/image.png":
+			if r.Header.Get("If-None-Match") == testETag {
+				atomic.AddInt32(notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", testETag)
+			w.Write(testImage)
+		case "/slow.png":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests, notModified
+}
+
+// newTestLoader creates a remote loader caching in $XDG_CACHE_HOME, set to a
+// test directory.
+func newTestLoader(t *testing.T, srv *httptest.Server) *Loader {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	return &Loader{
+		Sources: map[string]string{
+			"image.png":   srv.URL + "/image.png",
+			"slow.png":    srv.URL + "/slow.png",
+			"missing.png": srv.URL + "/missing.png",
+		},
+		Bundled:  fstest.MapFS{"missing.png": {Data: []byte("bundled")}},
+		Remote:   true,
+		CacheDir: DefaultCacheDir("gallery-test"),
+		Client:   srv.Client(),
+	}
+}
+
+func TestLoaderFetch(t *testing.T) {
+	srv, requests, _ := newTestServer(t)
+	l := newTestLoader(t, srv)
+
+	var last Progress
+	byts, e := l.Load(context.Background(), "image.png", func(p Progress) { last = p })
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(byts, testImage) {
+		t.Errorf("got %q, want %q", byts, testImage)
+	}
+	if last.Done != int64(len(testImage)) || last.Fraction() != 1 {
+		t.Errorf("last progress %+v, want all %d bytes", last, len(testImage))
+	}
+
+	// Loaded assets are kept in memory.
+	if _, e := l.Load(context.Background(), "image.png", nil); e != nil {
+		t.Fatal(e)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("%d requests, want 1", n)
+	}
+}
+
+func TestLoaderCacheRevalidation(t *testing.T) {
+	srv, _, notModified := newTestServer(t)
+	l := newTestLoader(t, srv)
+	if _, e := l.Load(context.Background(), "image.png", nil); e != nil {
+		t.Fatal(e)
+	}
+	if filepath.Dir(l.CacheDir) != filepath.Clean(os.Getenv("XDG_CACHE_HOME")) {
+		t.Fatalf("cache dir %s not in XDG_CACHE_HOME", l.CacheDir)
+	}
+
+	// A new loader, sharing the disk cache, revalidates with the ETag.
+	next := &Loader{Sources: l.Sources, Remote: true, CacheDir: l.CacheDir, Client: l.Client}
+	byts, e := next.Load(context.Background(), "image.png", nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(byts, testImage) {
+		t.Errorf("got %q from cache, want %q", byts, testImage)
+	}
+	if n := atomic.LoadInt32(notModified); n != 1 {
+		t.Errorf("%d answers 304, want 1", n)
+	}
+}
+
+func TestLoaderTimeout(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	l := newTestLoader(t, srv)
+	l.Timeout = 50 * time.Millisecond
+
+	_, e := l.Load(context.Background(), "slow.png", nil)
+	var errFetch *FetchError
+	if !errors.As(e, &errFetch) || errFetch.Key != "slow.png" {
+		t.Fatalf("got %v, want a *FetchError", e)
+	}
+	if !errors.Is(e, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", e)
+	}
+}
+
+func TestLoaderCancel(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	l := newTestLoader(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	l.LoadAsync(ctx, "slow.png", nil, func(_ []byte, e error) { done <- e })
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case e := <-done:
+		if !errors.Is(e, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("load not canceled")
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	l := newTestLoader(t, srv)
+
+	if _, e := l.Load(context.Background(), "unknown.png", nil); !errors.Is(e, ErrUnknownKey) {
+		t.Errorf("unknown key: got %v, want ErrUnknownKey", e)
+	}
+
+	// The HTTP error is returned with the bundled fallback.
+	byts, e := l.Load(context.Background(), "missing.png", nil)
+	var errHTTP *HTTPError
+	if !errors.As(e, &errHTTP) || errHTTP.StatusCode != http.StatusNotFound {
+		t.Fatalf("got %v, want a 404 *HTTPError", e)
+	}
+	if string(byts) != "bundled" {
+		t.Errorf("got %q, want the bundled fallback", byts)
+	}
+
+	// Without remote, the bundled file is used without error.
+	l.Remote = false
+	if byts, e := l.Load(context.Background(), "missing.png", nil); e != nil || string(byts) != "bundled" {
+		t.Errorf("offline: got %q, %v, want the bundled file", byts, e)
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/gallery/registry"
+	"github.com/gtkool4/grun"
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//------------------------------------------------------------[ COMMAND LINE ]--
+
+// cliOptions lists the gallery command line options. They're parsed by
+// GApplication with its own, and listed by --help. Options without argument
+// description are flags.
+var cliOptions = []struct {
+	Name, Arg, Description string
+}{
+	{"list", "", "Print the gallery entries and exit"},
+	{"show", "GROUP/ENTRY", "Open a window with a single entry, like Displays/Scale"},
+	{"group", "GROUP", "Open a window with a single group, like Buttons"},
+	{"theme", "NAME[:dark]", "Use a GTK theme, like Adwaita:dark"},
+	{"window-size", "WIDTHxHEIGHT", "Set the window size"},
+	{"no-network", "", "Only use the bundled images, even with " + assetsRemoteEnv},
+	{"smoke", "", "Create and check every entry, exit code 1 on failure"},
+	{"screenshots", "DIR", "Save a PNG image of each entry in DIR"},
+	{"screenshots-groups", "DIR", "Save a PNG image of each group page in DIR"},
+}
+
+// CommandLine holds the parsed gallery options.
+type CommandLine struct {
+	List              bool
+	Show              string
+	Group             string
+	Theme             string
+	WindowSize        string
+	NoNetwork         bool
+	Smoke             bool
+	Screenshots       string
+	ScreenshotsGroups string
+}
+
+// runCommandLine runs the application like gapp.Run, with the gallery options
+// added to the GApplication ones. Options select the widget created for the
+// window: the full gallery by default.
+func runCommandLine(args []string) int {
+	maker := func() gtk.Widgetter { return newGallery() }
+	var theme string
+	var e error
+	gapp.Init(func(*gtk.Application) {
+		if gapp.Win != nil { // Activated again, like by a second launch: already built.
+			gapp.Win.Present()
+			return
+		}
+		e = grun.Exec(func() gtk.Widgetter {
+			w := maker()
+			if theme != "" {
+				applyTheme(theme) // After the maker, to override the screenshots theme.
+			}
+			return w
+		})(gapp)
+	})
+
+	gapp.App.SetOptionContextSummary("Preview the GTK4 widgets, and their code.")
+	for _, opt := range cliOptions {
+		arg := glib.OptionArgString
+		if opt.Arg == "" {
+			arg = glib.OptionArgNone
+		}
+		gapp.App.AddMainOption(opt.Name, 0, glib.OptionFlagNone, arg, opt.Description, opt.Arg)
+	}
+
+	// Called before the application is registered, with the options parsed.
+	// A positive value exits with this code, -1 continues.
+	gapp.App.Connect("handle-local-options", func(_ *gtk.Application, opts *glib.VariantDict) int {
+		cl := parseCommandLine(opts)
+		if cl.List {
+			printEntries()
+			return 0
+		}
+		call, err := cl.Maker()
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		if cl.NoNetwork {
+			loader.Remote = false
+		}
+		if cl != (CommandLine{}) {
+			// Another gallery instance would get the activation, and ignore the options.
+			gapp.App.SetFlags(gapp.App.Flags() | gio.ApplicationNonUnique)
+		}
+		maker, theme = call, cl.Theme
+		return -1
+	})
+
+	code := gapp.App.Run(args)
+	if e != nil {
+		fmt.Printf(grun.FmtErrRun+"\n", e)
+		return 1
+	}
+	if gapp.ExitCode() != 0 {
+		return gapp.ExitCode()
+	}
+	return code
+}
+
+// parseCommandLine reads the gallery options from the GApplication options.
+func parseCommandLine(opts *glib.VariantDict) CommandLine {
+	str := func(name string) string {
+		if !opts.Contains(name) {
+			return ""
+		}
+		_, value := opts.LookupValue(name, glib.NewVariantType("s")).String()
+		return value
+	}
+	return CommandLine{
+		List:              opts.Contains("list"),
+		Show:              str("show"),
+		Group:             str("group"),
+		Theme:             str("theme"),
+		WindowSize:        str("window-size"),
+		NoNetwork:         opts.Contains("no-network"),
+		Smoke:             opts.Contains("smoke"),
+		Screenshots:       str("screenshots"),
+		ScreenshotsGroups: str("screenshots-groups"),
+	}
+}
+
+// Maker checks the options, applies the window size, and returns the maker of
+// the window widget.
+func (cl CommandLine) Maker() (func() gtk.Widgetter, error) {
+	if cl.WindowSize != "" {
+		var width, height int
+		if _, e := fmt.Sscanf(cl.WindowSize, "%dx%d", &width, &height); e != nil || width <= 0 || height <= 0 {
+			return nil, fmt.Errorf("--window-size: want WIDTHxHEIGHT, got %q", cl.WindowSize)
+		}
+		gapp.Width, gapp.Height = width, height
+	}
+	if _, variant := splitTheme(cl.Theme); variant != "" && variant != "dark" {
+		return nil, fmt.Errorf("--theme: unknown variant %q, only dark", variant)
+	}
+
+	switch {
+	case cl.Smoke:
+		return runSmoke, nil
+
+	case cl.Screenshots != "":
+		return func() gtk.Widgetter { return runScreenshots(cl.Screenshots, false) }, nil
+
+	case cl.ScreenshotsGroups != "":
+		return func() gtk.Widgetter { return runScreenshots(cl.ScreenshotsGroups, true) }, nil
+
+	case cl.Show != "":
+		split := strings.SplitN(cl.Show, "/", 2)
+		if len(split) != 2 {
+			return nil, fmt.Errorf("--show: want GROUP/ENTRY, got %q", cl.Show)
+		}
+		item, ok := registry.Lookup(split[0], split[1])
+		if !ok {
+			return nil, fmt.Errorf("--show: no entry %q, see --list", cl.Show)
+		}
+		return func() gtk.Widgetter { return withInspector(gtknew.ScrolledWindow(newEntryFrame(item))) }, nil
+
+	case cl.Group != "":
+		title, list, e := findGroup(cl.Group)
+		if e != nil {
+			return nil, e
+		}
+		return func() gtk.Widgetter { return withInspector(newGroupPage(title, list)) }, nil
+	}
+	return func() gtk.Widgetter { return newGallery() }, nil
+}
+
+// findGroup returns the group named title, ignoring case, and its real title.
+func findGroup(title string) (string, []registry.Entry, error) {
+	for _, group := range groups {
+		if strings.EqualFold(group.Title, title) {
+			return group.Title, group.List, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no group %q, see --list", title)
+}
+
+// printEntries prints the gallery entries, as used by --show, with their tags
+// and the GTK version needed, when newer than the running one.
+func printEntries() {
+	running := gtkVersion()
+	for _, group := range groups {
+		for _, item := range group.List {
+			tags := item.Tags
+			if !item.Supported(running) {
+				tags += " (requires GTK " + item.MinGTK.String() + ")"
+			}
+			fmt.Printf("%-30s %s\n", group.Title+"/"+item.Name, tags)
+		}
+	}
+}
+
+// splitTheme splits a theme option, like GTK_THEME: name[:variant].
+func splitTheme(theme string) (name, variant string) {
+	split := strings.SplitN(theme, ":", 2)
+	if len(split) == 2 {
+		return split[0], split[1]
+	}
+	return theme, ""
+}
+
+// applyTheme sets the GTK theme name and dark variant, from the theme option.
+func applyTheme(theme string) {
+	name, variant := splitTheme(theme)
+	settings := gtk.SettingsGetDefault()
+	if name != "" {
+		settings.SetObjectProperty("gtk-theme-name", name)
+	}
+	settings.SetObjectProperty("gtk-application-prefer-dark-theme", variant == "dark")
+}
@@ -0,0 +1,80 @@
+#include "customwidget.h"
+#include "_cgo_export.h"
+
+struct _GalleryCustom {
+	GtkBox parent_instance;
+	gboolean active;
+};
+
+G_DEFINE_TYPE(GalleryCustom, gallery_custom, GTK_TYPE_BOX)
+
+enum { PROP_ACTIVE = 1, N_PROPS };
+static GParamSpec *properties[N_PROPS];
+
+enum { TOGGLED, N_SIGNALS };
+static guint signals[N_SIGNALS];
+
+GtkWidget *gallery_custom_new(void) { return g_object_new(GALLERY_TYPE_CUSTOM, NULL); }
+
+gboolean gallery_custom_get_active(GalleryCustom *self) { return self->active; }
+
+// gallery_custom_set_active notifies the property and emits toggled, only when
+// the value changes.
+void gallery_custom_set_active(GalleryCustom *self, gboolean active) {
+	active = !!active;
+	if (self->active == active) {
+		return;
+	}
+	self->active = active;
+	g_object_notify_by_pspec(G_OBJECT(self), properties[PROP_ACTIVE]);
+	g_signal_emit(self, signals[TOGGLED], 0, active);
+}
+
+static void gallery_custom_set_property(GObject *object, guint prop_id, const GValue *value, GParamSpec *pspec) {
+	switch (prop_id) {
+	case PROP_ACTIVE:
+		gallery_custom_set_active(GALLERY_CUSTOM(object), g_value_get_boolean(value));
+		break;
+	default:
+		G_OBJECT_WARN_INVALID_PROPERTY_ID(object, prop_id, pspec);
+	}
+}
+
+static void gallery_custom_get_property(GObject *object, guint prop_id, GValue *value, GParamSpec *pspec) {
+	switch (prop_id) {
+	case PROP_ACTIVE:
+		g_value_set_boolean(value, GALLERY_CUSTOM(object)->active);
+		break;
+	default:
+		G_OBJECT_WARN_INVALID_PROPERTY_ID(object, prop_id, pspec);
+	}
+}
+
+static void gallery_custom_dispose(GObject *object) {
+	galleryCustomDispose((guintptr)object); // Go: drops the instance data.
+	G_OBJECT_CLASS(gallery_custom_parent_class)->dispose(object);
+}
+
+static void gallery_custom_class_init(GalleryCustomClass *klass) {
+	GObjectClass *object_class = G_OBJECT_CLASS(klass);
+	object_class->set_property = gallery_custom_set_property;
+	object_class->get_property = gallery_custom_get_property;
+	object_class->dispose = gallery_custom_dispose;
+
+	properties[PROP_ACTIVE] = g_param_spec_boolean("active", "Active", "Whether the widget is active",
+		FALSE, G_PARAM_READWRITE | G_PARAM_EXPLICIT_NOTIFY | G_PARAM_STATIC_STRINGS);
+	g_object_class_install_properties(object_class, N_PROPS, properties);
+
+	signals[TOGGLED] = g_signal_new("toggled", G_TYPE_FROM_CLASS(klass), G_SIGNAL_RUN_LAST,
+		0, NULL, NULL, NULL, G_TYPE_NONE, 1, G_TYPE_BOOLEAN);
+
+	gtk_widget_class_set_css_name(GTK_WIDGET_CLASS(klass), "gallery-custom");
+}
+
+// gallery_custom_init runs for every instance, created by Go or by a builder.
+static void gallery_custom_init(GalleryCustom *self) {
+	gtk_box_set_spacing(GTK_BOX(self), 10);
+	galleryCustomInit((guintptr)self); // Go: creates the children.
+}
+
+guintptr gallery_value_get_object(guintptr value) { return (guintptr)g_value_get_object((GValue *)value); }
@@ -0,0 +1,239 @@
+package main
+
+// #cgo pkg-config: gtk4
+// #include "customwidget.h"
+import "C"
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+
+	"github.com/gtkool4/gtkelp/buildhelp"
+)
+
+var listCustom = Group{
+	{"CustomWidget", newCustomWidget, "timer switch gobject subclass property signal"},
+	{"CustomWidget UI", newCustomWidgetUI, "builder xml gtype bind property"},
+}
+
+func newCustomWidget() gtk.Widgetter {
+	w := NewCustomWidget()
+	w.SetActive(true)
+	return w
+}
+
+// customWidgetUI declares a CustomWidget in builder XML, by its GType name, with
+// a check button bound to its active property.
+const customWidgetUI = `
+<interface>
+  <object class="GtkBox" id="box">
+    <property name="orientation">vertical</property>
+    <property name="spacing">6</property>
+    <child>
+      <object class="GalleryCustom" id="custom">
+        <property name="active">True</property>
+      </object>
+    </child>
+    <child>
+      <object class="GtkCheckButton">
+        <property name="label">Bound to the active property</property>
+        <property name="active" bind-source="custom" bind-property="active" bind-flags="bidirectional|sync-create"/>
+      </object>
+    </child>
+  </object>
+</interface>`
+
+func newCustomWidgetUI() gtk.Widgetter {
+	RegisterCustomWidget() // The builder finds the type by name: it must be registered first.
+	b := buildhelp.NewFromString(customWidgetUI)
+	box := b.Box("box")
+	testError(b.Errors())
+
+	custom := b.GetObject("custom").Cast().(*CustomWidget) // Cast by the registered marshaler.
+	custom.Connect("toggled", func(active bool) { events.Log("CustomUI", "toggled", active) })
+	return box
+}
+
+//
+//-----------------------------------------------------------[ CUSTOM WIDGET ]--
+
+// CustomWidget provides an example of custom widget: a GtkBox subclass,
+// registered as the GalleryCustom GType (see customwidget.c).
+//
+// As a real GObject type, it's used like GTK widgets: its active property is
+// listed by the inspector, bound to other properties or set in builder XML,
+// its toggled signal is connected by name, and it's styled with its CSS name,
+// gallery-custom.
+//
+// It displays a timer, running while it's shown, and provides a switch bound
+// to the active property that updates an icon and a text.
+type CustomWidget struct {
+	gtk.Box     // Extends the main container.
+	*customData // Go side of the instance.
+}
+
+// customData holds the Go side of a CustomWidget instance. It doesn't refer to
+// the instance, to not keep it alive.
+type customData struct {
+	sw         *gtk.Switch
+	img        *gtk.Image
+	labelState *gtk.Label
+	labelTime  *gtk.Label
+	timer      externglib.SourceHandle // Timer source, 0 when stopped.
+}
+
+// customInstances maps the CustomWidget instances to their Go data, from their
+// init to their dispose.
+var customInstances = make(map[uintptr]*customData)
+
+// customType is the GalleryCustom GType, set when registered.
+var customType externglib.Type
+
+const customCSS = `
+gallery-custom {
+	padding: 4px 8px;
+	border-radius: 6px;
+	background-color: alpha(@theme_selected_bg_color, 0.1);
+}
+`
+
+// RegisterCustomWidget registers the GalleryCustom GType, its Go marshaler
+// (casting instances to *CustomWidget) and its CSS. Call it before using the
+// type by name, like in a builder. It's done once.
+func RegisterCustomWidget() {
+	if customType != 0 {
+		return
+	}
+	customType = externglib.Type(C.gallery_custom_get_type())
+	externglib.RegisterGValueMarshalers([]externglib.TypeMarshaler{
+		{T: customType, F: marshalCustomWidget},
+	})
+
+	css := gtk.NewCSSProvider()
+	css.LoadFromData([]byte(customCSS))
+	gtk.StyleContextAddProviderForDisplay(gdk.DisplayGetDefault(), css, gtk.STYLE_PROVIDER_PRIORITY_APPLICATION)
+}
+
+// NewCustomWidget creates an example widget with timer and switch.
+func NewCustomWidget() *CustomWidget {
+	RegisterCustomWidget()
+	ptr := C.gallery_custom_new()
+	return externglib.Take(unsafe.Pointer(ptr)).Cast().(*CustomWidget)
+}
+
+func marshalCustomWidget(p uintptr) (interface{}, error) {
+	ptr := uintptr(C.gallery_value_get_object(C.guintptr(p)))
+	return &CustomWidget{Box: *boxFromNative(ptr), customData: customInstances[ptr]}, nil
+}
+
+// boxFromNative wraps a GtkBox instance, or a subclass instance, as a gtk.Box.
+func boxFromNative(ptr uintptr) *gtk.Box {
+	value := externglib.InitValue(externglib.TypeFromName("GtkBox"))
+	value.SetInstance(ptr)
+	return value.GoValue().(*gtk.Box) // Marshaled as the value type, not the instance one.
+}
+
+// galleryCustomInit creates the children of a new instance.
+//
+//export galleryCustomInit
+func galleryCustomInit(ptr C.guintptr) {
+	data := &customData{
+		sw:         gtk.NewSwitch(),
+		img:        gtk.NewImage(),
+		labelState: gtk.NewLabel(""),
+		labelTime:  gtk.NewLabel(""),
+	}
+	customInstances[uintptr(ptr)] = data
+
+	data.labelTime.SetHAlign(gtk.AlignEnd)
+	data.labelTime.SetHExpand(true)
+	data.updateState(false)
+
+	box := boxFromNative(uintptr(ptr))
+
+	// Bound to the switch active property, without state-set handler: the
+	// switch state follows at once (see newSwitch for the delayed mode).
+	BindProperty(box, "active", data.sw, "active", BindingBidirectional|BindingSyncCreate)
+
+	// Callbacks only refer to the data: the instance can be freed.
+	box.Connect("toggled", func(active bool) {
+		data.updateState(active)
+		events.Log("Custom", "toggled", active)
+	})
+	box.Connect("map", data.Start)
+	box.Connect("unmap", data.Stop)
+
+	// Packing
+	box.Append(gtk.NewLabel("Custom Widget:"))
+	box.Append(data.sw)
+	box.Append(data.img)
+	box.Append(data.labelState)
+	box.Append(data.labelTime)
+}
+
+// galleryCustomDispose drops the Go data of a disposed instance.
+//
+//export galleryCustomDispose
+func galleryCustomDispose(ptr C.guintptr) {
+	if data, ok := customInstances[uintptr(ptr)]; ok {
+		data.Stop()
+		delete(customInstances, uintptr(ptr))
+	}
+}
+
+// Widget Public API.
+
+// Active returns the active property, in sync with the switch.
+func (w *CustomWidget) Active() bool { return w.ObjectProperty("active") == true }
+
+// SetActive sets the active property, emitting toggled when changed.
+func (w *CustomWidget) SetActive(active bool) { w.SetObjectProperty("active", active) }
+
+// Start starts the timer, updating the time every second. It's started when
+// the widget is shown (mapped).
+//
+// The timer is a GLib timeout source: its callback runs in the gtk main loop,
+// so it can update the widget directly, and no goroutine is left running.
+func (w *customData) Start() {
+	if w.timer != 0 {
+		return
+	}
+	w.updateTime()
+	w.timer = externglib.TimeoutSecondsAdd(1, func() bool {
+		w.updateTime()
+		return true // Keep the source.
+	})
+	events.Log("Custom", "timer", "start")
+}
+
+// Stop stops the timer. It's stopped when the widget is hidden (unmapped),
+// like when its page is hidden or its window closed.
+func (w *customData) Stop() {
+	if w.timer == 0 {
+		return
+	}
+	externglib.SourceRemove(w.timer)
+	w.timer = 0
+	events.Log("Custom", "timer", "stop")
+}
+
+// Running returns true when the timer is started.
+func (w *customData) Running() bool { return w.timer != 0 }
+
+// Widget Private Callbacks.
+func (w *customData) updateTime() {
+	w.labelTime.SetLabel(fmt.Sprintf("Last updated at %s.", time.Now().Format(time.StampMilli)))
+}
+
+func (w *customData) updateState(active bool) {
+	text := map[bool]string{false: "Inactive", true: "Active"}
+	icon := map[bool]string{false: "go-down", true: "go-up"}
+	w.img.SetFromIconName(icon[active])
+	w.labelState.SetLabel(text[active])
+}
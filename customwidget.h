@@ -0,0 +1,22 @@
+#ifndef GALLERY_CUSTOM_H
+#define GALLERY_CUSTOM_H
+
+#include <gtk/gtk.h>
+
+G_BEGIN_DECLS
+
+// GalleryCustom is the GType of the CustomWidget: a GtkBox with an "active"
+// property and a "toggled" signal, and "gallery-custom" as CSS name.
+#define GALLERY_TYPE_CUSTOM (gallery_custom_get_type())
+G_DECLARE_FINAL_TYPE(GalleryCustom, gallery_custom, GALLERY, CUSTOM, GtkBox)
+
+GtkWidget *gallery_custom_new(void);
+gboolean gallery_custom_get_active(GalleryCustom *self);
+void gallery_custom_set_active(GalleryCustom *self, gboolean active);
+
+// gallery_value_get_object returns the object of a GValue, for the Go marshaler.
+guintptr gallery_value_get_object(guintptr value);
+
+G_END_DECLS
+
+#endif
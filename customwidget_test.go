@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// TestCustomWidgetTimer checks the CustomWidget timer runs only while it's
+// shown: its GLib source is removed when unmapped or destroyed.
+func TestCustomWidgetTimer(t *testing.T) {
+	requireDisplay(t)
+
+	var w *CustomWidget
+	var win *gtk.Window
+	var criticals int
+	onMain(func() {
+		countCriticals()
+		criticals = criticalsCount()
+		w = NewCustomWidget()
+		win = gtk.NewWindow()
+		win.SetChild(w)
+		win.Show()
+	})
+	if !iterateUntil(time.Second, w.Mapped) {
+		t.Fatal("not mapped")
+	}
+	if w.timer == 0 {
+		t.Fatal("timer not started when mapped")
+	}
+
+	// Unmapped: the source is removed, the time isn't updated anymore.
+	onMain(win.Hide)
+	if !iterateUntil(time.Second, func() bool { return !w.Mapped() }) {
+		t.Fatal("not unmapped")
+	}
+	if w.timer != 0 {
+		t.Fatalf("timer source %d left when unmapped", w.timer)
+	}
+	checkTimeStopped(t, w)
+
+	// Started again when shown, stopped when the window is destroyed.
+	onMain(win.Show)
+	if !iterateUntil(time.Second, func() bool { return w.timer != 0 }) {
+		t.Fatal("timer not started when mapped again")
+	}
+	onMain(win.Destroy)
+	if !iterateUntil(time.Second, func() bool { return w.timer == 0 }) {
+		t.Fatalf("timer source %d left when destroyed", w.timer)
+	}
+	checkTimeStopped(t, w)
+
+	// Stop is idempotent.
+	onMain(func() {
+		w.Stop()
+		w.Stop()
+	})
+	var n int
+	onMain(func() { n = criticalsCount() - criticals })
+	if n > 0 {
+		t.Errorf("%d GTK critical", n)
+	}
+}
+
+// checkTimeStopped checks the time label isn't updated, over a few timer
+// periods of main loop.
+func checkTimeStopped(t *testing.T, w *CustomWidget) {
+	t.Helper()
+	var text string
+	onMain(func() { text = w.labelTime.Label() })
+	for i := 0; i < 3; i++ {
+		changed := iterateUntil(time.Second, func() bool { return w.labelTime.Label() != text })
+		if changed || w.timer != 0 {
+			t.Fatalf("time updated when stopped, tick %d, source %d", i, w.timer)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//---------------------------------------------------------------[ EVENT LOG ]--
+
+// events records the signals emitted by the gallery widgets.
+var events = &EventLog{Mirror: true}
+
+// maxEvents limits the number of events kept in memory.
+const maxEvents = 5000
+
+// Event defines a signal emission recorded by the event log.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Entry  string    `json:"entry"`  // Gallery entry name.
+	Signal string    `json:"signal"` // Signal name.
+	Args   []string  `json:"args,omitempty"`
+}
+
+// String formats the event as a log line.
+func (e Event) String() string {
+	return fmt.Sprintf("%s [%s] %s %s", e.Time.Format("15:04:05.000"), e.Entry, e.Signal, strings.Join(e.Args, " "))
+}
+
+// EventLog records gallery events. It must be used from the GTK main loop.
+type EventLog struct {
+	Mirror bool // Also print events to stdout.
+	Paused bool // Drop new events.
+
+	list      []Event
+	listeners []func(Event)
+}
+
+// Log records a signal emission with its decoded arguments.
+func (l *EventLog) Log(entry, signal string, args ...interface{}) {
+	if l.Paused {
+		return
+	}
+	ev := Event{Time: time.Now(), Entry: entry, Signal: signal}
+	for _, arg := range args {
+		ev.Args = append(ev.Args, fmt.Sprint(arg))
+	}
+
+	l.list = append(l.list, ev)
+	if len(l.list) > maxEvents {
+		l.list = l.list[len(l.list)-maxEvents:]
+	}
+	if l.Mirror {
+		fmt.Println(ev)
+	}
+	for _, call := range l.listeners {
+		call(ev)
+	}
+}
+
+// Events returns the recorded events.
+func (l *EventLog) Events() []Event { return l.list }
+
+// Clear removes all recorded events.
+func (l *EventLog) Clear() { l.list = nil }
+
+// OnEvent registers a callback for each new event.
+func (l *EventLog) OnEvent(call func(Event)) { l.listeners = append(l.listeners, call) }
+
+// WriteJSON exports events as JSON lines.
+func (l *EventLog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, ev := range l.list {
+		if e := enc.Encode(ev); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// ExportFile exports events as JSON lines to the file path.
+func (l *EventLog) ExportFile(path string) error {
+	f, e := os.Create(path)
+	if e != nil {
+		return e
+	}
+	buf := bufio.NewWriter(f)
+	e = l.WriteJSON(buf)
+	if e == nil {
+		e = buf.Flush()
+	}
+	if ec := f.Close(); e == nil {
+		e = ec
+	}
+	return e
+}
+
+// callLog creates a signal callback that records the event.
+func callLog(entry, signal string, args ...interface{}) func() {
+	return func() { events.Log(entry, signal, args...) }
+}
+
+//
+//-----------------------------------------------------------[ EVENT CONSOLE ]--
+
+// newEventConsole creates the event log panel, with filters and actions.
+func newEventConsole() gtk.Widgetter {
+	tv := gtk.NewTextView()
+	tv.SetEditable(false)
+	tv.SetCursorVisible(false)
+	tv.SetMonospace(true)
+	buffer := tv.Buffer()
+
+	entryFilter := gtk.NewSearchEntry()
+	signalFilter := gtk.NewSearchEntry()
+	entryFilter.SetObjectProperty("placeholder-text", "Widget")
+	signalFilter.SetObjectProperty("placeholder-text", "Signal")
+
+	match := func(ev Event) bool {
+		return strings.Contains(strings.ToLower(ev.Entry), strings.ToLower(entryFilter.Text())) &&
+			strings.Contains(strings.ToLower(ev.Signal), strings.ToLower(signalFilter.Text()))
+	}
+	appendEvent := func(ev Event) {
+		end := buffer.EndIter()
+		buffer.Insert(&end, ev.String()+"\n", -1)
+		end = buffer.EndIter()
+		tv.ScrollToIter(&end, 0, false, 0, 0)
+	}
+	refresh := func() {
+		buffer.SetText("", -1)
+		for _, ev := range events.Events() {
+			if match(ev) {
+				appendEvent(ev)
+			}
+		}
+	}
+
+	events.OnEvent(func(ev Event) {
+		if match(ev) {
+			appendEvent(ev)
+		}
+	})
+	entryFilter.Connect("search-changed", refresh)
+	signalFilter.Connect("search-changed", refresh)
+
+	pause := gtk.NewToggleButton()
+	pause.SetIconName("media-playback-pause")
+	pause.SetTooltipText("Pause recording")
+	pause.Connect("toggled", func() { events.Paused = pause.Active() })
+
+	clear := gtk.NewButtonFromIconName("edit-clear-all")
+	clear.SetTooltipText("Clear")
+	clear.Connect("clicked", func() { events.Clear(); refresh() })
+
+	export := gtk.NewButtonFromIconName("document-save")
+	export.SetTooltipText("Export to JSON lines")
+	export.Connect("clicked", exportEvents)
+
+	mirror := gtk.NewCheckButtonWithLabel("stdout")
+	mirror.SetTooltipText("Also print events to stdout")
+	mirror.SetActive(events.Mirror)
+	mirror.Connect("toggled", func() { events.Mirror = mirror.Active() })
+
+	refresh()
+	toolbar := gtknew.HBox(boxMargin, gtk.NewLabel("Events"), entryFilter, signalFilter, pause, clear, export, mirror)
+	scroll := gtknew.ScrolledWindow(tv)
+	scroll.SetVExpand(true)
+	return gtknew.VBox(boxMargin, toolbar, scroll)
+}
+
+// exportEvents asks for a file and saves the events log as JSON lines.
+func exportEvents() {
+	w := gtk.NewFileChooserNative("Export events", &gapp.Win.Window, gtk.FileChooserActionSave, "_Save", "_Cancel")
+	w.SetCurrentName("gallery-events.jsonl")
+	w.Connect("response", func(_ *gtk.FileChooserNative, resp int) {
+		if resp == int(gtk.ResponseAccept) {
+			path := w.File().Path()
+			if e := events.ExportFile(path); e != nil {
+				fmt.Println("can't export events:", e)
+			}
+		}
+		w.Destroy()
+	})
+	w.Show()
+}
@@ -3,12 +3,11 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"math"
-	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/diamondburned/gotk4/pkg/cairo"
 	"github.com/diamondburned/gotk4/pkg/gdk/v4"
@@ -19,6 +18,9 @@ import (
 
 	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
 
+	"github.com/gtkool4/gallery/assets"
+	"github.com/gtkool4/gallery/liststore"
+	"github.com/gtkool4/gallery/registry"
 	"github.com/gtkool4/grun"
 	"github.com/gtkool4/gtkelp/buildhelp"
 	"github.com/gtkool4/gtkelp/gtknew"
@@ -26,10 +28,6 @@ import (
 
 const boxMargin = 2
 
-const imageSource = "https://raw.githubusercontent.com/golang-samples/gopher-vector/master/"
-
-var files = map[string][]byte{}
-
 //
 //--------------------------------------------------------------[ LAUNCH APP ]--
 
@@ -38,133 +36,256 @@ var gapp = &grun.App{
 	Title:  "GTK4 Gallery",
 	Width:  800,
 	Height: 800,
+	OnStop: func(*gtk.Application) { loaderCancel() },
 }
 
 func main() {
-	gapp.Run(func() gtk.Widgetter {
-		// Preload pixbuf data for iconview.
-		files["gotk4.png"] = downloadFile("https://avatars.githubusercontent.com/u/13782055?s=200&v=4")
-		files["gopher-front.png"] = downloadFile(imageSource + "gopher-front.png")
-		files["gopher-side.png"] = downloadFile(imageSource + "gopher-side_color.png")
-		files["gopher.png"] = downloadFile(imageSource + "gopher.png")
-
-		// Create widgets groups.
-		widgets := make([]gtk.Widgetter, 6)
-		titles := []string{"Displays", "Buttons", "Entries", "Containers", "Windows"}
-		for i, list := range []Group{listDisplays, listButtons, listEntries, listContainers, listWindows} {
-			widgets[i] = list.Widgets(titles[i])
-		}
-		widgets[5] = NewCustomWidgetStarted()
-
-		box := gtknew.VBox(10, widgets...)
-		return gtknew.ScrolledWindow(box)
-	})
+	os.Exit(runCommandLine(os.Args))
 }
 
 //
 //------------------------------------------------------------[ WIDGETS LIST ]--
 
-// Group defines a group of widget makers.
+// newGallery creates the gallery browser: a sidebar with a page for each group,
+// each page only created when first shown.
+func newGallery() gtk.Widgetter {
+	stack := NewLazyStack()
+	for _, group := range groups {
+		group := group // We're in a loop, so we need to make a static copy for the callback.
+		stack.AddLazy(group.Title, group.Title, func() gtk.Widgetter { return newGroupPage(group.Title, group.List) })
+	}
+	stack.AddLazy("Info", "Info", newInfoPage)
+
+	search.Filter(stack, func() {
+		for _, group := range groups {
+			stack.SetPageVisible(group.Title, search.MatchGroup(group.Title, group.List))
+		}
+		stack.SetPageVisible("Info", search.Match("Info", "version about gtk glib pango"))
+	})
+
+	paned := gtknew.VPaned(withInspector(stack.WithSidebar()), newEventConsole())
+	paned.SetPosition(gapp.Height * 3 / 4)
+	paned.SetVExpand(true)
+	return gtknew.VBox(0, newGallerySearch(), paned)
+}
+
+// withInspector packs the gallery, or a part of it for --show and --group, with
+// the inspector. It creates the inspector and installs the actions: it's called
+// once, for the window.
+func withInspector(w gtk.Widgetter) gtk.Widgetter {
+	installActions()
+	inspector = NewInspector()
+	w.SetHExpand(true)
+	w.SetVExpand(true)
+	return gtknew.HBox(0, w, inspector)
+}
+
+// groups lists the widget groups displayed in the gallery, in display order:
+// the gallery ones, then those registered by other packages.
+var groups []registry.Group
+
+// groups is set in init, after the registration of the gallery entries. The
+// print report lists it from a widget maker (an initialization cycle otherwise).
+func init() {
+	builtin := []struct {
+		Title string
+		List  Group
+	}{
+		{"Displays", listDisplays},
+		{"Buttons", listButtons},
+		{"Entries", listEntries},
+		{"Containers", listContainers},
+		{"Lists", listLists},
+		{"Windows", listWindows},
+		{"Input", listInput},
+		{"Custom", listCustom},
+	}
+	var titles []string
+	for _, group := range builtin {
+		group.List.Register(group.Title)
+		titles = append(titles, group.Title)
+	}
+
+	// Imported packages registered first: move their groups after ours.
+	groups = registry.Groups(titles...)
+}
+
+// Group declares a group of the gallery widget makers. Other packages use the
+// registry directly.
 type Group []struct {
 	Name string
 	Make func() gtk.Widgetter
+	Tags string // Free text keywords, used by the search.
 }
 
-// Widgets creates a box with all widgets in the group.
-func (l Group) Widgets(title string) gtk.Widgetter {
-	var widgets []gtk.Widgetter
+// builtinOptions sets more metadata of the gallery entries, by entry name.
+var builtinOptions = map[string][]registry.Option{
+	"MenuButton":    {registry.MinGTK(4, 4, 0), registry.Description("The activate signal needs GTK 4.4.")},
+	"FontButton":    {registry.MinGTK(4, 4, 0), registry.Description("The activate signal needs GTK 4.4.")},
+	"GestureZoom":   {registry.Description("Needs a touchscreen, or a touchpad pinch.")},
+	"GestureRotate": {registry.Description("Needs a touchscreen, or a touchpad twist.")},
+	"GestureSwipe":  {registry.Description("Works with the mouse too: drag and release quickly.")},
+}
+
+// Register adds the group entries to the registry.
+func (l Group) Register(title string) {
 	for _, item := range l {
-		widgets = append(widgets, gtknew.Frame(item.Name, item.Make()))
+		opts := append([]registry.Option{registry.Tags(item.Tags)}, builtinOptions[item.Name]...)
+		registry.Register(title, item.Name, item.Make, opts...)
+	}
+}
+
+// newGroupWidgets creates a box with all widgets in the group.
+func newGroupWidgets(title string, list []registry.Entry) gtk.Widgetter {
+	var widgets []gtk.Widgetter
+	for _, item := range list {
+		widgets = append(widgets, newEntryFrame(item))
 	}
 	isWide := (title == "Containers")
-	return gtknew.Frame(title, newContainer(isWide, widgets...))
+	box := gtknew.Frame(title, newContainer(isWide, widgets...))
+
+	search.Filter(box, func() {
+		for i, item := range list {
+			search.filterFrame(widgets[i], title, item.Name, item.Tags)
+		}
+	})
+	return box
+}
+
+// newGroupPage creates a browsable page for the group, with an overview of all
+// its widgets and a page for each entry. Pages are created when first shown.
+func newGroupPage(title string, list []registry.Entry) gtk.Widgetter {
+	stack := NewLazyStack()
+	stack.AddLazy("overview", "Overview", func() gtk.Widgetter {
+		return gtknew.ScrolledWindow(newGroupWidgets(title, list))
+	})
+	for _, item := range list {
+		item := item // We're in a loop, so we need to make a static copy for the callback.
+		stack.AddLazy(item.Name, item.Name, func() gtk.Widgetter {
+			return gtknew.ScrolledWindow(newEntryFrame(item))
+		})
+	}
+
+	search.Filter(stack, func() {
+		for _, item := range list {
+			stack.SetPageVisible(item.Name, search.Match(title, item.Name, item.Tags))
+		}
+	})
+	return stack.WithSidebar()
+}
+
+// newEntryFrame creates the entry widget in a frame, with its source code and
+// the property inspector on click. Entries needing a newer GTK show a card
+// instead.
+func newEntryFrame(item registry.Entry) gtk.Widgetter {
+	if !item.Supported(gtkVersion()) {
+		return gtknew.Frame(item.Name, newVersionCard(item))
+	}
+
+	w := item.Make()
+	toggle, code := newCodeToggle(item)
+	frame := gtknew.Frame(item.Name, gtknew.VBox(boxMargin, w, toggle, code))
+	if item.Description != "" {
+		frame.SetTooltipText(item.Description)
+	}
+
+	// Show the widget properties when clicked. The capture phase lets us see the
+	// click before the widget handles it. There's no inspector in the
+	// screenshots and smoke test windows.
+	if inspector != nil {
+		click := gtk.NewGestureClick()
+		click.SetPropagationPhase(gtk.PhaseCapture)
+		click.Connect("pressed", func() { inspector.Inspect(item.Name, w) })
+		frame.AddController(click)
+	}
+	return frame
 }
 
 var listDisplays = Group{
-	{"Label", newLabel},
-	{"Spinner", newSpinner},
-	{"StatusBar", newStatusBar},
-	{"LevelBar", newLevelBar},
-	{"ProgressBar", newProgressBar},
-	{"InfoBar", newInfoBar},
-	{"ScrollBar", newScrollbar},
-	{"Image", newImage},
-	{"Picture", newPicture},
-	{"Separator", newSeparator},
-	{"TextView", newTextView},
-	{"Scale", newScale},
-	{"DrawingArea", newDrawingArea},
-	{"Video", newVideo},
-	{"MediaControls", newMediaControls},
-	{"WindowControls", newWindowControls},
-	{"MenuBar", newMenuBar},
-	{"Calendar", newCalendar},
-	{"EmojiChooser", placeholder},
-	{"Menu", placeholder},
+	{"Label", newLabel, "text caption markup"},
+	{"Spinner", newSpinner, "busy loading wait progress"},
+	{"StatusBar", newStatusBar, "message status footer"},
+	{"LevelBar", newLevelBar, "gauge meter battery strength"},
+	{"ProgressBar", newProgressBar, "percent loading fraction"},
+	{"InfoBar", newInfoBar, "message notification banner"},
+	{"ScrollBar", newScrollbar, "scroll slider adjustment"},
+	{"Image", newImage, "icon picture graphic"},
+	{"Picture", newPicture, "image pixbuf photo graphic"},
+	{"Separator", newSeparator, "line divider"},
+	{"TextView", newTextView, "multiline text editor buffer"},
+	{"Scale", newScale, "slider range value"},
+	{"DrawingArea", newDrawingArea, "cairo canvas draw paint"},
+	{"Video", newVideo, "media movie player"},
+	{"MediaControls", newMediaControls, "media play pause player"},
+	{"WindowControls", newWindowControls, "titlebar minimize maximize close"},
+	{"MenuBar", newMenuBar, "menu actions"},
+	{"Calendar", newCalendar, "date day month year"},
+	{"EmojiChooser", newEmojiChooser, "emoji smiley picker"},
+	{"Menu", placeholder, "actions popup"},
 }
 
 var listButtons = Group{
-	{"Button", newButton},
-	{"ToggleButton", newToggleGroup},
-	{"LinkButton", newLinkButton},
-	{"CheckButton", newCheckButton},
-	{"RadioButton", newRadioGroup},
-	{"MenuButton", newMenuButton},
-	{"LockButton", newLockButton},
-	{"VolumeButton", newVolumeButton},
-	{"Switch", newSwitch},
-	{"ComboBox", newComboBox},
-	{"ComboBoxText", newComboBoxText},
-	{"DropDown", newDropDown},
-	{"ColorButton", newColorButton},
-	{"FontButton", newFontButton},
-	{"ApplicationButton", newApplicationButton},
+	{"Button", newButton, "click action"},
+	{"ToggleButton", newToggleGroup, "toggle group on off"},
+	{"LinkButton", newLinkButton, "url hyperlink web"},
+	{"CheckButton", newCheckButton, "checkbox option boolean"},
+	{"RadioButton", newRadioGroup, "option choice group exclusive"},
+	{"MenuButton", newMenuButton, "menu actions popover"},
+	{"LockButton", newLockButton, "permission unlock admin"},
+	{"VolumeButton", newVolumeButton, "audio sound level"},
+	{"Switch", newSwitch, "toggle on off boolean"},
+	{"ComboBox", newComboBox, "select choice list model"},
+	{"ComboBoxText", newComboBoxText, "select choice list"},
+	{"DropDown", newDropDown, "select choice list combo"},
+	{"ColorButton", newColorButton, "colour rgba picker"},
+	{"FontButton", newFontButton, "font typeface picker"},
+	{"ApplicationButton", newApplicationButton, "app chooser mime open with"},
 }
 
 var listEntries = Group{
-	{"Entry", newEntry},
-	{"SearchEntry", newSearchEntry},
-	{"PasswordEntry", newPasswordEntry},
-	{"Spinbutton", newSpinButton},
-	{"EditableLabel", newEditableLabel},
+	{"Entry", newEntry, "text input field"},
+	{"SearchEntry", newSearchEntry, "text input find filter"},
+	{"PasswordEntry", newPasswordEntry, "text input secret hidden"},
+	{"Spinbutton", newSpinButton, "number input value"},
+	{"EditableLabel", newEditableLabel, "text input rename inline"},
 }
 
 var listContainers = Group{
-	{"Box", newBox},
-	{"Grid", newGrid},
-	{"CenterBox", newCenterBox},
-	{"ScrolledWindow", newScrolledWindow},
-	{"Paned", newPaned},
-	{"Frame", newFrame},
-	{"Expander", newExpander},
-	{"SearchBar", newSearchBar},
-	{"ActionBar", newActionBar},
-	{"HeaderBar", newHeaderBar},
-	{"Notebook", newNotebook},
-	{"ListBox", newListBox},
-	{"FlowBox", newFlowBox},
-	{"TreeView", newTreeView},
-	{"Iconview", newIconview},
-	{"Overlay", newOverlay},
-	{"StackSwitcher", newStackSwitcher},
-	{"StackSidebar", newStackSidebar},
-	{"PopOver", newPopOver},
+	{"Box", newBox, "layout horizontal vertical pack"},
+	{"Grid", newGrid, "layout table rows columns"},
+	{"CenterBox", newCenterBox, "layout center start end"},
+	{"ScrolledWindow", newScrolledWindow, "scroll viewport"},
+	{"Paned", newPaned, "split resize divider"},
+	{"Frame", newFrame, "border label group"},
+	{"Expander", newExpander, "collapse reveal hide"},
+	{"SearchBar", newSearchBar, "find filter toolbar"},
+	{"ActionBar", newActionBar, "toolbar bottom buttons"},
+	{"HeaderBar", newHeaderBar, "titlebar title"},
+	{"Notebook", newNotebook, "tabs pages"},
+	{"ListBox", newListBox, "list rows"},
+	{"FlowBox", newFlowBox, "grid reflow wrap"},
+	{"TreeView", newTreeView, "table tree list model columns"},
+	{"Iconview", newIconview, "icons grid model"},
+	{"Overlay", newOverlay, "layer stack on top"},
+	{"StackSwitcher", newStackSwitcher, "pages tabs stack"},
+	{"StackSidebar", newStackSidebar, "pages navigation stack"},
+	{"PopOver", newPopOver, "popup bubble menu"},
 }
 
 var listWindows = Group{
-	{"Window", newWindow},
-	{"Dialog", newDialog},
-	{"CustomDialog", newCustomDialog},
-	{"MessageDialog", newMessageDialog},
-	{"AboutDialog", newAboutDialog},
-	{"Assistant", newAssistant},
-	{"PageSetupDialog", newPrintPageSetupDialog},
-	{"PrintDialog", newPrintDialog},
-	{"ShortcutsWindow", newShortcutsWindow},
-	{"ColorChooser", newColorChooser},
-	{"FileChooser", newFileChooser},
-	{"FontChooser", newFontChooser},
-	{"AppchooserDialog", newAppchooserDialog},
+	{"Window", newWindow, "toplevel new window"},
+	{"Dialog", newDialog, "modal popup response"},
+	{"CustomDialog", newCustomDialog, "builder xml ui modal"},
+	{"MessageDialog", newMessageDialog, "alert question modal"},
+	{"AboutDialog", newAboutDialog, "credits license version"},
+	{"Assistant", newAssistant, "wizard steps pages"},
+	{"PageSetupDialog", newPrintPageSetupDialog, "print paper page"},
+	{"PrintDialog", newPrintDialog, "print printer pdf"},
+	{"ShortcutsWindow", newShortcutsWindow, "keyboard keys help accelerators"},
+	{"ColorChooser", newColorChooser, "colour rgba palette picker"},
+	{"FileChooser", newFileChooser, "open save file folder path"},
+	{"FontChooser", newFontChooser, "font typeface"},
+	{"AppchooserDialog", newAppchooserDialog, "application mime open with"},
 }
 
 //
@@ -217,8 +338,20 @@ func newImage() gtk.Widgetter {
 }
 
 func newPicture() gtk.Widgetter {
-	pix := pixbufLoader(files["gotk4.png"])
-	return gtk.NewPictureForPixbuf(pix)
+	w := gtk.NewPictureForPixbuf(placeholderPixbuf())
+	bar := gtk.NewProgressBar()
+	progress := func(p assets.Progress) {
+		if f := p.Fraction(); f >= 0 {
+			bar.SetFraction(f)
+		} else {
+			bar.Pulse()
+		}
+	}
+	loadPixbuf(w, "gotk4.png", progress, func(pix *gdkpixbuf.Pixbuf) {
+		w.SetPixbuf(pix)
+		bar.SetVisible(false)
+	})
+	return gtknew.VBox(boxMargin, w, bar)
 }
 
 func newSeparator() gtk.Widgetter {
@@ -259,16 +392,20 @@ func newDrawingArea() gtk.Widgetter {
 	w.SetContentWidth(100)
 	w.SetContentHeight(100)
 	w.SetDrawFunc(func(area *gtk.DrawingArea, cr *cairo.Context, width int, height int) {
-		w, h := float64(width), float64(height)
-		for i, color := range [][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
-			cr.Arc(w*(float64(i+1))/4, h/2, math.Min(w, h)/2, 0, 2*math.Pi)
-			cr.SetSourceRGBA(color[0], color[1], color[2], 0.7)
-			cr.Fill()
-		}
+		drawCircles(cr, float64(width), float64(height))
 	})
 	return gtknew.VBox(boxMargin, w)
 }
 
+// drawCircles draws the DrawingArea sample, also used by the print report.
+func drawCircles(cr *cairo.Context, w, h float64) {
+	for i, color := range [][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		cr.Arc(w*(float64(i+1))/4, h/2, math.Min(w, h)/2, 0, 2*math.Pi)
+		cr.SetSourceRGBA(color[0], color[1], color[2], 0.7)
+		cr.Fill()
+	}
+}
+
 func newVideo() gtk.Widgetter {
 	w := gtk.NewVideo()
 	return w
@@ -285,10 +422,19 @@ func newWindowControls() gtk.Widgetter {
 	return w
 }
 
+// The GTK3 MenuBar is replaced by PopoverMenuBar, built from a menu model.
 func newMenuBar() gtk.Widgetter {
-	return placeholder()
-	// w:=gtk.NewMenuBar()
-	// return w
+	view, app, quit := appMenuSections() // Same actions as the MenuButton.
+	file := gio.NewMenu()
+	file.AppendSection("", quit)
+	help := gio.NewMenu()
+	help.AppendSection("", app)
+
+	menu := gio.NewMenu()
+	menu.AppendSubmenu("_File", file)
+	menu.AppendSubmenu("_View", view)
+	menu.AppendSubmenu("_Help", help)
+	return gtknew.VBox(boxMargin, gtk.NewPopoverMenuBarFromModel(menu))
 }
 
 func newCalendar() gtk.Widgetter {
@@ -298,10 +444,58 @@ func newCalendar() gtk.Widgetter {
 // gtk.EmojiChooser.Realize is ambiguous
 // cannot use w (type *gtk.EmojiChooser) as type gtk.Widgetter in argument to toBox:
 // *gtk.EmojiChooser does not implement gtk.Widgetter (missing Realize method)
+//
+// Using .Widget to prevent the naming conflict.
 func newEmojiChooser() gtk.Widgetter {
-	return placeholder()
-	// w:=gtk.NewEmojiChooser()
-	// return gtknew.VBox(boxMargin,w)
+	entry := gtk.NewEntry()
+	entry.SetHExpand(true)
+	recent := gtknew.HBox(0)
+	var history []string // Recent emojis, newest first.
+
+	insert := func(emoji string) {
+		// Inserted in the buffer, the rest of the text and the undo history are
+		// kept. The cursor only moves for text inserted before it: move it after.
+		pos := entry.Position()
+		n := entry.Buffer().InsertText(uint(pos), emoji, -1)
+		entry.SetPosition(pos + int(n))
+	}
+
+	addRecent := func(emoji string) {
+		for i, old := range history { // Move an existing emoji to the front.
+			if old == emoji {
+				history = append(history[:i], history[i+1:]...)
+				break
+			}
+		}
+		history = append([]string{emoji}, history...)
+		if len(history) > 8 {
+			history = history[:8]
+		}
+
+		for child := recent.FirstChild(); child != nil; child = recent.FirstChild() {
+			recent.Remove(child)
+		}
+		for _, emoji := range history {
+			emoji := emoji // We're in a loop, so we need to make a static copy for the callback.
+			btn := gtk.NewButtonWithLabel(emoji)
+			btn.SetHasFrame(false)
+			btn.Connect("clicked", func() { insert(emoji) })
+			recent.Append(btn)
+		}
+	}
+
+	chooser := gtk.NewEmojiChooser()
+	chooser.Connect("emoji-picked", func(_ *gtk.EmojiChooser, text string) {
+		events.Log("EmojiChooser", "emoji-picked", text)
+		insert(text)
+		addRecent(text)
+	})
+
+	btn := gtk.NewMenuButton()
+	btn.SetIconName("face-smile")
+	btn.SetPopover(&chooser.Widget)
+
+	return gtknew.VBox(boxMargin, gtknew.HBox(boxMargin, entry, &btn.Widget), recent)
 }
 
 func newMenu() gtk.Widgetter { return placeholder() }
@@ -311,10 +505,10 @@ func newMenu() gtk.Widgetter { return placeholder() }
 
 func newButton() gtk.Widgetter {
 	w := gtk.NewButtonWithLabel("Button")
-	w.Connect("clicked", callPrint("button 1 clicked"))
+	w.Connect("clicked", callLog("Button", "clicked", "button 1"))
 
 	x := gtk.NewButtonFromIconName("preferences-system")
-	x.Connect("clicked", callPrint("button 2 clicked"))
+	x.Connect("clicked", callLog("Button", "clicked", "button 2"))
 	return gtknew.VBox(boxMargin, w, x)
 }
 
@@ -322,14 +516,14 @@ func newCheckButton() gtk.Widgetter {
 	btn1 := gtk.NewCheckButtonWithLabel("CheckButton")
 	btn2 := gtk.NewCheckButtonWithLabel("Not checked")
 	btn1.SetActive(true)
-	btn1.Connect("toggled", callPrint("check 1 toggled"))
-	btn2.Connect("toggled", callPrint("check 2 toggled"))
+	btn1.Connect("toggled", func() { events.Log("CheckButton", "toggled", "check 1", btn1.Active()) })
+	btn2.Connect("toggled", func() { events.Log("CheckButton", "toggled", "check 2", btn2.Active()) })
 	return gtknew.VBox(boxMargin, btn1, btn2)
 }
 
 func newLinkButton() gtk.Widgetter {
 	w := gtk.NewLinkButtonWithLabel("https://golang.org/", "Link Button")
-	w.Connect("clicked", callPrint("link button clicked"))
+	w.Connect("clicked", callLog("LinkButton", "clicked", w.URI()))
 	return gtknew.VBox(boxMargin, w)
 }
 
@@ -348,7 +542,7 @@ func newToggleGroup() gtk.Widgetter {
 		box.Append(btn)
 
 		i := i // We're in a loop, so we need to make a static copy of the index for the callback.
-		btn.Connect("toggled", func() { fmt.Println("toggle button", i, btn.Active()) })
+		btn.Connect("toggled", func() { events.Log("ToggleButton", "toggled", i, btn.Active()) })
 	}
 	return box
 }
@@ -368,43 +562,20 @@ func newRadioGroup() gtk.Widgetter {
 		box.Append(btn)
 
 		i := i // We're in a loop, so we need to make a static copy of the index for the callback.
-		btn.Connect("toggled", func() { fmt.Println("radio button", i, btn.Active()) })
+		btn.Connect("toggled", func() { events.Log("RadioButton", "toggled", i, btn.Active()) })
 	}
 	return box
 }
 
 func newMenuButton() gtk.Widgetter {
 	btn := gtk.NewMenuButton()
-	btn.Connect("activate", callPrint("menu button value changed")) // since gtk 4.4
-
-	isMaximized := gapp.Win.IsMaximized()
-	vMax := glib.NewVariantBoolean(isMaximized)
-	actFullScreen := gio.NewSimpleActionStateful("fullscreen", nil, vMax)
-	actQuit := gio.NewSimpleAction("quit", nil)
-
-	actFullScreen.Connect("change-state", func() { // Args: *gio.SimpleAction, *glib.Variant  (the variant crash ATM)
-		fmt.Println("menu actFullScreen", vMax.Boolean())
-		newval := !gapp.Win.IsMaximized()
-		if newval {
-			gapp.Win.Maximize()
-		} else {
-			gapp.Win.Unmaximize()
-		}
-		vMax = glib.NewVariantBoolean(newval)
-		actFullScreen.SetState(vMax)
-	})
-
-	actQuit.Connect("activate", func() {
-		fmt.Println("menu actQuit")
-		gapp.App.Quit()
-	})
-
-	gapp.Win.AddAction(actFullScreen)
-	gapp.App.AddAction(actQuit)
+	btn.Connect("activate", callLog("MenuButton", "activate")) // since gtk 4.4
 
+	view, app, quit := appMenuSections() // Actions are set in installActions.
 	menu := gio.NewMenu()
-	menu.Append("FullScreen", "win.fullscreen") //"app.action")
-	menu.Append("Quit", "app.quit")
+	menu.AppendSection("", view)
+	menu.AppendSection("", app)
+	menu.AppendSection("", quit)
 	btn.SetDirection(gtk.ArrowNone) // Hide the button arrow and restore the default button icon.
 	btn.SetMenuModel(menu)
 
@@ -413,78 +584,113 @@ func newMenuButton() gtk.Widgetter {
 
 func newLockButton() gtk.Widgetter {
 	w := gtk.NewLockButton(gio.NewSimplePermission(false))
-	w.Connect("clicked", callPrint("lock button clicked"))
+	w.Connect("clicked", callLog("LockButton", "clicked"))
 	return w
 }
 
 func newVolumeButton() gtk.Widgetter {
 	w := gtk.NewVolumeButton()
-	w.Connect("value-changed", callPrint("volume button value changed"))
+	w.Connect("value-changed", func() { events.Log("VolumeButton", "value-changed", w.Value()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
+// switchCheckDelay simulates the duration of an async check, in ms.
+const switchCheckDelay = 1000
+
+// newSwitch shows both ways to handle a switch change.
+//
+// Immediate: the state-set handler returns false, the default handler sets the
+// state to the requested value at once.
+//
+// Delayed: the handler returns true, the switch moves (active) but its state
+// waits to be set by SetState, here after a simulated async check. Refusing
+// restores the previous state, and moves the switch back.
+//
+// In both cases, the requested state is the signal argument, and the handler
+// must be connected with Connect, before the default one. The default handler
+// returns true (handled), which stops the emission: a handler connected with
+// ConnectAfter is never called. notify::active follows the switch moves.
 func newSwitch() gtk.Widgetter {
-	btn1 := gtk.NewSwitch()
-	btn2 := gtk.NewSwitch()
-	btn1.SetActive(true)
-	btn1.Connect("activate", callPrint("switch 1 activated"))
-	btn2.Connect("activate", callPrint("switch 2 activated"))
+	immediate := gtk.NewSwitch()
+	immediate.SetActive(true)
+	immediate.Connect("state-set", func(_ *gtk.Switch, state bool) bool {
+		events.Log("Switch", "state-set", "immediate", state)
+		return false // Let the default handler set the state.
+	})
+	immediate.Connect("notify::active", func() { events.Log("Switch", "notify::active", "immediate", immediate.Active()) })
+
+	delayed := gtk.NewSwitch()
+	allow := gtk.NewCheckButtonWithLabel("Allow")
+	allow.SetActive(true)
+	status := gtk.NewLabel("")
+	delayed.Connect("state-set", func(_ *gtk.Switch, state bool) bool {
+		events.Log("Switch", "state-set", "delayed", state)
+		delayed.SetSensitive(false)
+		status.SetText("Checking...")
+		externglib.TimeoutAdd(switchCheckDelay, func() {
+			if !allow.Active() {
+				state = !state // Refused: restore the previous state.
+			}
+			delayed.SetState(state)
+			delayed.SetSensitive(true)
+			status.SetText(map[bool]string{false: "Off", true: "On"}[state])
+		})
+		return true // Handled: the state is set later.
+	})
+	delayed.Connect("notify::active", func() { events.Log("Switch", "notify::active", "delayed", delayed.Active()) })
+
+	grid := gtk.NewGrid()
+	grid.SetColumnSpacing(boxMargin * 2)
+	grid.SetRowSpacing(boxMargin)
+	grid.Attach(gtk.NewLabel("Immediate"), 0, 0, 1, 1)
+	grid.Attach(immediate, 1, 0, 1, 1)
+	grid.Attach(gtk.NewLabel("Delayed"), 0, 1, 1, 1)
+	grid.Attach(delayed, 1, 1, 1, 1)
+	grid.Attach(gtknew.HBox(boxMargin, allow, status), 2, 1, 1, 1)
 
 	box := gtk.NewCenterBox()
-	box.SetCenterWidget(gtknew.VBox(boxMargin, btn1, btn2))
+	box.SetCenterWidget(grid)
 	return box
 }
 
-// ModelCB defines data fields order for the ListStore (TreeView, IconView, ComboBox).
-const ( // Must match the ListStore declaration order.
-	ModelCBRef = iota
-	ModelCBText
-	ModelCBTooltip
-	ModelCBIcon
-)
-
 func newComboBox() gtk.Widgetter {
-	model := gtk.NewListStore([]externglib.Type{
-		externglib.TypeString,
-		externglib.TypeString,
-	})
-
-	for _, item := range [][]string{
+	type choice struct {
+		Ref  string
+		Text string
+	}
+	model := liststore.New(choice{})
+	for _, item := range []choice{
 		{"0", "ComboBox"},
 		{"1", "with"},
 		{"2", "choices"},
 	} {
-		insertWithValues(model, map[int]interface{}{
-			ModelCBRef:  item[0],
-			ModelCBText: item[1],
-		})
+		model.Append(item)
 	}
 
 	list := gtk.NewComboBoxWithModel(model)
-	list.SetIDColumn(ModelCBRef)
-	list.SetEntryTextColumn(ModelCBText)
+	list.SetIDColumn(model.Column("Ref"))
+	list.SetEntryTextColumn(model.Column("Text"))
 
 	// Don't forget to add a cell renderer.
 	cellText := gtk.NewCellRendererText()
 	list.PackStart(cellText, false)
-	list.AddAttribute(cellText, "markup", ModelCBText)
+	model.Bind(list, cellText, "markup", "Text")
 	list.SetActiveID("0")
 
 	// Get values examples.
 	iter, ok := list.ActiveIter()
 	if ok {
-		gval := model.Value(&iter, ModelCBText)
-		if gval.GoValue() != "ComboBox" {
-			fmt.Println("error combobox iter value is not", "ComboBox")
+		var active choice
+		model.Get(&iter, &active)
+		if active != (choice{"0", "ComboBox"}) {
+			fmt.Println("error combobox iter value is not", "ComboBox", active)
 		}
 	}
 
 	content := []string{}
-	model.Foreach(func(model gtk.TreeModeller, path *gtk.TreePath, iter *gtk.TreeIter) (stop bool) {
-		gval := model.Value(iter, ModelCBText)
-		content = append(content, gval.GoValue().(string))
-		return false
-	})
+	for _, row := range model.Rows().([]choice) {
+		content = append(content, row.Text)
+	}
 	if strings.Join(content, " ") != "ComboBox with choices" {
 		fmt.Println("error combobox values modified")
 	}
@@ -498,7 +704,7 @@ func newComboBoxText() gtk.Widgetter {
 	w.AppendText("is easier")
 	w.AppendText("to implement")
 	w.SetActive(0)
-	w.Connect("changed", callPrint("combo box text selection changed"))
+	w.Connect("changed", func() { events.Log("ComboBoxText", "changed", w.ActiveText()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
@@ -510,20 +716,24 @@ func newColorButton() gtk.Widgetter {
 	color := gdk.NewRGBA(1, 0, 0, 1)
 	w := gtk.NewColorButton()
 	w.SetRGBA(&color)
-	w.Connect("color-set", callPrint("color button color set"))
+	w.Connect("color-set", func() { col := w.RGBA(); events.Log("ColorButton", "color-set", col.String()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
 func newFontButton() gtk.Widgetter {
 	w := gtk.NewFontButton()
-	w.Connect("activate", callPrint("font button activated")) // since gtk 4.4
-	w.Connect("font-set", func() { fmt.Println("font button new font set:", w.FontDesc()) })
+	w.Connect("activate", callLog("FontButton", "activate")) // since gtk 4.4
+	w.Connect("font-set", func() { events.Log("FontButton", "font-set", w.Font()) })
 	return gtknew.VBox(boxMargin, &w.Widget)
 }
 
 func newApplicationButton() gtk.Widgetter {
 	w := gtk.NewAppChooserButton("video/avi")
-	w.Connect("changed", callPrint("application button changed"))
+	w.Connect("changed", func() {
+		if info := w.AppInfo(); info != nil {
+			events.Log("ApplicationButton", "changed", info.Name())
+		}
+	})
 	return gtknew.VBox(boxMargin, w)
 }
 
@@ -533,14 +743,14 @@ func newApplicationButton() gtk.Widgetter {
 func newEntry() gtk.Widgetter {
 	w := gtk.NewEntry()
 	w.Buffer().SetText("Entry", -1)
-	w.Connect("changed", func() { fmt.Printf("entry changed: '%s'\n", w.Buffer().Text()) })
+	w.Connect("changed", func() { events.Log("Entry", "changed", w.Buffer().Text()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
 func newSearchEntry() gtk.Widgetter {
 	w := gtk.NewSearchEntry()
 	w.SetText("SearchEntry")
-	w.Connect("search-changed", func() { fmt.Printf("search entry changed: '%s'\n", w.Text()) })
+	w.Connect("search-changed", func() { events.Log("SearchEntry", "search-changed", w.Text()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
@@ -548,21 +758,21 @@ func newPasswordEntry() gtk.Widgetter {
 	w := gtk.NewPasswordEntry()
 	w.SetText("PasswordEntry")
 	w.SetShowPeekIcon(true)
-	w.Connect("changed", func() { fmt.Printf("password entry changed: '%s'\n", w.Text()) })
+	w.Connect("changed", func() { events.Log("PasswordEntry", "changed", w.Text()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
 func newSpinButton() gtk.Widgetter {
 	w := gtk.NewSpinButtonWithRange(1, 100, 1)
 	w.SetValue(42)
-	w.Connect("changed", func() { fmt.Printf("spin button changed: '%s'\n", w.Text()) })
+	w.Connect("changed", func() { events.Log("Spinbutton", "changed", w.Text()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
 func newEditableLabel() gtk.Widgetter {
 	w := gtk.NewEditableLabel("EditableLabel")
 	w.StartEditing()
-	w.Connect("changed", func() { fmt.Printf("editable entry changed: '%s'\n", w.Text()) })
+	w.Connect("changed", func() { events.Log("EditableLabel", "changed", w.Text()) })
 	return gtknew.VBox(boxMargin, w)
 }
 
@@ -680,7 +890,7 @@ func newNotebook() gtk.Widgetter {
 	w.AppendPage(gtk.NewLabel("Notebook"), gtk.NewLabel("Page 1"))
 	w.AppendPage(gtk.NewLabel("another"), gtk.NewLabel("Page 2"))
 	w.AppendPage(gtk.NewLabel("page"), gtk.NewLabel("Page 3"))
-	w.Connect("switch-page", callPrint("notebook page changed"))
+	w.Connect("switch-page", func(_ *gtk.Notebook, _ gtk.Widgetter, page uint) { events.Log("Notebook", "switch-page", page) })
 	return w
 }
 
@@ -708,44 +918,106 @@ func newFlowBox() gtk.Widgetter {
 }
 
 func newTreeView() gtk.Widgetter {
-	// Order in the model must match the const declaration order (ModelCB...)
-	model := gtk.NewListStore([]externglib.Type{
-		externglib.TypeString, //  REF
-		externglib.TypeString, //  TEXT
-		externglib.TypeString, //  COMMENT
-		externglib.TypeInt,    //  VALUE
-	})
-
-	for _, data := range []struct { // Fill the model with values.
-		ref, text, comment string
-		value              float64
-	}{
-		{"0", "<b><big>TreeView</big></b>", "text 1", 20},
-		{"1", "with", "text 2", 50},
-		{"2", "texts", "<s>text 3</s>", 80},
+	type row struct {
+		Ref, Text, Comment string
+		Value              int
+		Done               bool
+		Kind               string
+	}
+	model := liststore.New(row{})
+	for _, data := range []row{ // Fill the model with values.
+		{"0", "<b><big>TreeView</big></b>", "text 1", 20, true, "Widget"},
+		{"1", "with", "text 2", 50, false, "Model"},
+		{"2", "texts", "<s>text 3</s>", 80, false, "Renderer"},
 	} {
-		insertWithValues(model, map[int]interface{}{
-			ModelCBRef:         data.ref,
-			ModelCBText:        data.text,
-			ModelCBTooltip:     data.comment,
-			ModelCBTooltip + 1: data.value,
-		})
+		model.Append(data)
 	}
 
-	// Create TreeView
+	// Create TreeView. Rows can be reordered by drag and drop.
 	w := gtk.NewTreeViewWithModel(model)
+	w.SetReorderable(true)
+
+	// Undo stack, of the last edits first. The undos find their row by
+	// TreeRowReference, kept up to date when other rows are inserted or
+	// removed, and return false if it's gone. Deleting a row clears the stack,
+	// as older undos may refer to it: with the remove button, or by drag and
+	// drop, that inserts a copy of the moved row and deletes the original.
+	var undo []func() bool
+	var undoing bool
+	btnUndo := gtk.NewButtonFromIconName("edit-undo")
+	btnUndo.SetTooltipText("Undo")
+	btnUndo.SetSensitive(false)
+	clearUndo := func() {
+		undo = nil
+		btnUndo.SetSensitive(false)
+	}
+	btnUndo.Connect("clicked", func() {
+		undoing = true
+		done := undo[len(undo)-1]()
+		undoing = false
+		if !done { // Out of sync with the model, drop it all.
+			clearUndo()
+			return
+		}
+		undo = undo[:len(undo)-1]
+		btnUndo.SetSensitive(len(undo) > 0)
+		events.Log("TreeView", "undo")
+	})
+	pushUndo := func(call func() bool) {
+		undo = append(undo, call)
+		btnUndo.SetSensitive(true)
+	}
+	model.Connect("row-deleted", func() {
+		if !undoing {
+			clearUndo()
+		}
+	})
+
+	// rowIter returns the iter of a referenced row, or false if it was deleted.
+	rowIter := func(ref *gtk.TreeRowReference) (gtk.TreeIter, bool) {
+		path := ref.Path()
+		if path == nil {
+			return gtk.TreeIter{}, false
+		}
+		return model.Iter(path)
+	}
+
+	// edit writes the edited value back to the model, by path.
+	//
+	// The edited signals are emitted while the cell editor is still in the tree
+	// view: changing the row at once fails with a Gtk-CRITICAL on its CSS node
+	// (gtk_css_node_insert_after). The write is deferred until it's removed.
+	edit := func(path, field string, value interface{}) {
+		externglib.IdleAdd(func() {
+			iter, ok := model.Iter(gtk.NewTreePathFromString(path))
+			if !ok {
+				return
+			}
+			var old row
+			model.Get(&iter, &old)
+			model.SetField(&iter, field, value)
+			ref := gtk.NewTreeRowReference(model, model.Path(&iter))
+			pushUndo(func() bool {
+				iter, ok := rowIter(ref)
+				if ok {
+					model.Set(&iter, old)
+				}
+				return ok
+			})
+			events.Log("TreeView", "edited", path, field, value)
+		})
+	}
 
 	// Add editable text column
 	cellText := gtk.NewCellRendererText()
 	cellText.SetObjectProperty("editable", true)
-	cellText.Connect("edited",
-		func(_ *gtk.CellRendererText, path, text string) { fmt.Println(text) })
+	cellText.Connect("edited", func(_ *gtk.CellRendererText, path, text string) { edit(path, "Text", text) })
 	columnText := gtk.NewTreeViewColumn()
 	columnText.SetTitle("Name")
 	columnText.SetResizable(true)
 
 	columnText.PackEnd(cellText, false)
-	columnText.AddAttribute(cellText, "markup", ModelCBText)
+	model.Bind(columnText, cellText, "markup", "Text")
 	w.AppendColumn(columnText)
 
 	// Add simple text column
@@ -754,65 +1026,140 @@ func newTreeView() gtk.Widgetter {
 	columnTooltip.SetTitle("Comment")
 
 	columnTooltip.PackEnd(cellTooltip, false)
-	columnTooltip.AddAttribute(cellTooltip, "markup", ModelCBTooltip)
+	model.Bind(columnTooltip, cellTooltip, "markup", "Comment")
 	w.AppendColumn(columnTooltip)
 
+	// Add toggle column
+	cellToggle := gtk.NewCellRendererToggle()
+	cellToggle.Connect("toggled", func(_ *gtk.CellRendererToggle, path string) {
+		iter, ok := model.Iter(gtk.NewTreePathFromString(path))
+		if ok {
+			var data row
+			model.Get(&iter, &data)
+			edit(path, "Done", !data.Done)
+		}
+	})
+	columnToggle := gtk.NewTreeViewColumn()
+	columnToggle.SetTitle("Done")
+	columnToggle.PackEnd(cellToggle, false)
+	model.Bind(columnToggle, cellToggle, "active", "Done")
+	w.AppendColumn(columnToggle)
+
+	// Add spin column, editing the value shown by the progress bar.
+	cellSpin := gtk.NewCellRendererSpin()
+	cellSpin.SetObjectProperty("adjustment", gtk.NewAdjustment(0, 0, 100, 1, 10, 0))
+	cellSpin.SetObjectProperty("editable", true)
+	cellSpin.Connect("edited", func(_ *gtk.CellRendererSpin, path, text string) {
+		if value, e := strconv.Atoi(text); e == nil {
+			edit(path, "Value", value)
+		}
+	})
+	columnSpin := gtk.NewTreeViewColumn()
+	columnSpin.SetTitle("Value")
+	columnSpin.PackEnd(cellSpin, false)
+	model.Bind(columnSpin, cellSpin, "text", "Value")
+	w.AppendColumn(columnSpin)
+
 	// Add progress bar column
 	cellProgress := gtk.NewCellRendererProgress()
 	cellProgress.SetObjectProperty("value", 100)
 	columnProgress := gtk.NewTreeViewColumn()
 
 	columnProgress.PackEnd(cellProgress, false)
-	columnProgress.AddAttribute(cellProgress, "value", ModelCBTooltip+1)
+	model.Bind(columnProgress, cellProgress, "value", "Value")
 	w.AppendColumn(columnProgress)
 
-	return &w.Widget
+	// Add combo column, with choices from another model.
+	type kind struct{ Name string }
+	kinds := liststore.New(kind{})
+	for _, name := range []string{"Widget", "Model", "Renderer"} {
+		kinds.Append(kind{name})
+	}
+	cellCombo := gtk.NewCellRendererCombo()
+	cellCombo.SetObjectProperty("model", kinds.ListStore)
+	cellCombo.SetObjectProperty("text-column", kinds.Column("Name"))
+	cellCombo.SetObjectProperty("has-entry", false)
+	cellCombo.SetObjectProperty("editable", true)
+	cellCombo.Connect("edited", func(_ *gtk.CellRendererCombo, path, text string) { edit(path, "Kind", text) })
+	columnCombo := gtk.NewTreeViewColumn()
+	columnCombo.SetTitle("Kind")
+	columnCombo.PackEnd(cellCombo, false)
+	model.Bind(columnCombo, cellCombo, "text", "Kind")
+	w.AppendColumn(columnCombo)
+
+	// Add and remove rows.
+	count := len(model.Rows().([]row))
+	btnAdd := gtk.NewButtonFromIconName("list-add")
+	btnAdd.SetTooltipText("Add a row")
+	btnAdd.Connect("clicked", func() {
+		data := row{Ref: strconv.Itoa(count), Text: "new row", Kind: "Widget"}
+		count++
+		iter := model.Append(data)
+		ref := gtk.NewTreeRowReference(model, model.Path(&iter))
+		pushUndo(func() bool {
+			iter, ok := rowIter(ref)
+			if ok {
+				model.Remove(&iter)
+			}
+			return ok
+		})
+		events.Log("TreeView", "row-added", data.Ref)
+	})
+
+	btnRemove := gtk.NewButtonFromIconName("list-remove")
+	btnRemove.SetTooltipText("Remove the selected row")
+	btnRemove.Connect("clicked", func() {
+		_, iter, ok := w.Selection().Selected()
+		if !ok {
+			return
+		}
+		var data row
+		model.Get(&iter, &data)
+		pos := model.Path(&iter).Indices()[0]
+		model.Remove(&iter) // Clears the undo stack.
+		pushUndo(func() bool {
+			iter := model.Insert(pos)
+			model.Set(&iter, data)
+			return true
+		})
+		events.Log("TreeView", "row-removed", data.Ref)
+	})
+
+	return gtknew.VBox(boxMargin, gtknew.HBox(boxMargin, btnAdd, btnRemove, btnUndo), &w.Widget)
 }
 
 func newIconview() gtk.Widgetter {
-	// Order in the model must match the const declaration order (ModelCB...)
-	model := gtk.NewListStore([]externglib.Type{
-		externglib.TypeInt,                   // REF. The key column can also be an int.
-		externglib.TypeString,                // TEXT
-		externglib.TypeString,                // TOLTIP
-		externglib.TypeFromName("GdkPixbuf"), // ICON
-	})
+	type icon struct {
+		Ref           int // The key column can also be an int.
+		Text, Tooltip string
+		Icon          *gdkpixbuf.Pixbuf `gtype:"GdkPixbuf"`
+	}
+	model := liststore.New(icon{})
+	w := gtk.NewIconViewWithModel(model)
+	placeholder := placeholderPixbuf()
 
 	for _, data := range []struct { // Fill the model with values.
-		ref                 int
-		text, tooltip, icon string
+		icon
+		file string
 	}{
-		{0, "<big>Iconview</big>", "tooltip 1", "gotk4.png"},
-		{1, "with", "tooltip 2", "gopher-front.png"},
-		{2, "icons", "tooltip 3", "gopher-side.png"},
-		{3, "and <b>tooltips</b>", "tooltip 4", "gopher.png"},
+		{icon{0, "<big>Iconview</big>", "tooltip 1", placeholder}, "gotk4.png"},
+		{icon{1, "with", "tooltip 2", placeholder}, "gopher-front.png"},
+		{icon{2, "icons", "tooltip 3", placeholder}, "gopher-side.png"},
+		{icon{3, "and <b>tooltips</b>", "tooltip 4", placeholder}, "gopher.png"},
 	} {
-		iter := model.Append()
-		model.SetValue(&iter, ModelCBRef, externglib.NewValue(data.ref))
-		model.SetValue(&iter, ModelCBText, externglib.NewValue(data.text))
-		model.SetValue(&iter, ModelCBTooltip, externglib.NewValue(data.tooltip))
+		iter := model.Append(data.icon)
 
-		content := files[data.icon]
-
-		// TODO: find why this can't be loaded here (http.Transport panic).
-		// Should be:
-		// content := downloadFile(imageSource + data.icon)
-
-		externglib.IdleAdd(func() {
-
-			pixbuf := pixbufLoader(content)
-			if pixbuf != nil {
-				model.SetValue(&iter, ModelCBIcon, externglib.NewValue(pixbuf))
-			}
+		// Images are loaded in background, and replace the placeholder when ready.
+		loadPixbuf(&w.Widget, data.file, nil, func(pixbuf *gdkpixbuf.Pixbuf) {
+			model.SetField(&iter, "Icon", pixbuf)
 		})
 	}
 
-	w := gtk.NewIconViewWithModel(model)
 	w.SetActivateOnSingleClick(true)
-	w.SetMarkupColumn(ModelCBText)
-	w.SetTooltipColumn(ModelCBTooltip)
-	w.SetPixbufColumn(ModelCBIcon)
-	w.Connect("selection-changed", callPrint("iconview selection changed"))
+	w.SetMarkupColumn(model.Column("Text"))
+	w.SetTooltipColumn(model.Column("Tooltip"))
+	w.SetPixbufColumn(model.Column("Icon"))
+	w.Connect("selection-changed", callLog("Iconview", "selection-changed"))
 
 	return gtknew.VBox(boxMargin, &w.Widget)
 }
@@ -862,7 +1209,130 @@ func newStackSidebar() gtk.Widgetter {
 	return box
 }
 
-func newPopOver() gtk.Widgetter { return placeholder() }
+func newPopOver() gtk.Widgetter {
+	// Plain popover with any content.
+	entry := gtk.NewEntry()
+	plain := gtk.NewPopover()
+	closeBtn := gtk.NewButtonWithLabel("Close")
+	closeBtn.Connect("clicked", func() { plain.Popdown() })
+	plain.SetChild(gtknew.VBox(boxMargin, gtk.NewLabel("Any widget fits in a popover"), entry, closeBtn))
+	plain.Connect("closed", func() { events.Log("PopOver", "closed", entry.Text()) })
+
+	btnPlain := gtk.NewMenuButton()
+	btnPlain.SetLabel("Popover")
+	btnPlain.SetPopover(&plain.Widget)
+
+	// Menu popover, built from a model.
+	menu := newPopoverMenu()
+	btnMenu := gtk.NewMenuButton()
+	btnMenu.SetLabel("PopoverMenu")
+	btnMenu.SetPopover(&menu.Widget)
+
+	// Popover pointing to the clicked position.
+	area := gtk.NewLabel("Click anywhere in this area")
+	area.SetSizeRequest(-1, 80)
+	pointed := gtk.NewPopover()
+	pointed.SetChild(gtk.NewLabel("Pointing here"))
+	pointed.SetParent(area)
+	area.Connect("destroy", func() { pointed.Unparent() })
+	click := gtk.NewGestureClick()
+	click.Connect("pressed", func(_ *gtk.GestureClick, _ int, x, y float64) {
+		events.Log("PopOver", "pressed", int(x), int(y))
+		rect := gdk.NewRectangle(int(x), int(y), 1, 1)
+		pointed.SetPointingTo(&rect)
+		pointed.Popup()
+	})
+	area.AddController(click)
+
+	// Controls, applied to all popovers.
+	popovers := []*gtk.Popover{plain, &menu.Popover, pointed}
+	position := gtk.NewDropDownFromStrings([]string{"Left", "Right", "Top", "Bottom"})
+	position.SetSelected(uint(gtk.PosBottom))
+	position.Connect("notify::selected", func() {
+		for _, pop := range popovers {
+			pop.SetPosition(gtk.PositionType(position.Selected()))
+		}
+	})
+	arrow := gtk.NewCheckButtonWithLabel("Arrow")
+	arrow.SetActive(true)
+	arrow.Connect("toggled", func() {
+		for _, pop := range popovers {
+			pop.SetHasArrow(arrow.Active())
+		}
+	})
+	autohide := gtk.NewCheckButtonWithLabel("Autohide")
+	autohide.SetActive(true)
+	autohide.Connect("toggled", func() {
+		for _, pop := range popovers {
+			pop.SetAutohide(autohide.Active())
+		}
+	})
+
+	return gtknew.VBox(boxMargin,
+		gtknew.HBox(boxMargin, gtk.NewLabel("Position"), position, arrow, autohide),
+		gtknew.HBox(boxMargin, &btnPlain.Widget, &btnMenu.Widget),
+		gtknew.Frame("", area),
+	)
+}
+
+// newPopoverMenu creates a menu with sections, a submenu, stateful actions and
+// a custom widget. Its actions are in the "pop" group, set on the popover.
+func newPopoverMenu() *gtk.PopoverMenu {
+	group := gio.NewSimpleActionGroup()
+
+	for _, name := range []string{"copy", "paste"} {
+		name := name // We're in a loop, so we need to make a static copy for the callback.
+		act := gio.NewSimpleAction(name, nil)
+		act.Connect("activate", callLog("PopOver", "activate", "pop."+name))
+		group.AddAction(act)
+	}
+
+	// Boolean state, toggled by the default activate handler.
+	bold := gio.NewSimpleActionStateful("bold", nil, glib.NewVariantBoolean(false))
+	bold.Connect("notify::state", func() { events.Log("PopOver", "change-state", "pop.bold", bold.State().Boolean()) })
+	group.AddAction(bold)
+
+	// String state used as radio, set to the parameter of the activated item.
+	align := gio.NewSimpleActionStateful("align", glib.NewVariantType("s"), glib.NewVariantString("left"))
+	align.Connect("notify::state", func() {
+		_, value := align.State().String()
+		events.Log("PopOver", "change-state", "pop.align", value)
+	})
+	group.AddAction(align)
+
+	edit := gio.NewMenu()
+	edit.Append("Copy", "pop.copy")
+	edit.Append("Paste", "pop.paste")
+
+	format := gio.NewMenu()
+	format.Append("Bold", "pop.bold")
+
+	alignments := gio.NewMenu()
+	alignments.Append("Left", "pop.align::left")
+	alignments.Append("Center", "pop.align::center")
+	alignments.Append("Right", "pop.align::right")
+	format.AppendSubmenu("Alignment", alignments)
+
+	zoom := gio.NewMenuItem("Zoom", "")
+	zoom.SetAttributeValue("custom", glib.NewVariantString("zoom"))
+	custom := gio.NewMenu()
+	custom.AppendItem(zoom)
+
+	menu := gio.NewMenu()
+	menu.AppendSection("", edit)
+	menu.AppendSection("Format", format)
+	menu.AppendSection("", custom)
+
+	pop := gtk.NewPopoverMenuFromModel(menu)
+	pop.InsertActionGroup("pop", group)
+
+	scale := gtk.NewScaleWithRange(gtk.OrientationHorizontal, 50, 200, 10)
+	scale.SetValue(100)
+	scale.SetHExpand(true)
+	scale.Connect("value-changed", func() { events.Log("PopOver", "value-changed", "zoom", scale.Value()) })
+	pop.AddChild(gtknew.HBox(boxMargin, gtk.NewLabel("Zoom"), scale), "zoom")
+	return pop
+}
 
 //
 //-----------------------------------------------------------------[ WINDOWS ]--
@@ -886,7 +1356,7 @@ func newDialog() gtk.Widgetter {
 		w.AddButton("_Cancel", int(gtk.ResponseCancel))
 		w.SetDefaultResponse(0)
 		w.ContentArea().Append(gtk.NewLabel("Dialog\n\nwith buttons"))
-		w.Connect("response", func(d *gtk.Dialog, resp int) { fmt.Println("dialog response", resp); w.Destroy() })
+		w.Connect("response", func(d *gtk.Dialog, resp int) { events.Log("Dialog", "response", resp); w.Destroy() })
 
 		w.Show()
 	})
@@ -915,34 +1385,113 @@ func newCustomDialog() gtk.Widgetter {
 
 		w := b.Dialog("dialog1")
 		testError(b.Errors())
-		w.Connect("response", func(d *gtk.Dialog, resp int) { fmt.Println("custom dialog response", resp); w.Destroy() })
+		w.Connect("response", func(d *gtk.Dialog, resp int) { events.Log("CustomDialog", "response", resp); w.Destroy() })
 		w.Show()
 	})
 }
 
-func newMessageDialog() gtk.Widgetter { return placeholder() }
+var (
+	messageTypes = []gtk.MessageType{gtk.MessageInfo, gtk.MessageWarning, gtk.MessageQuestion, gtk.MessageError, gtk.MessageOther}
+	buttonsTypes = []gtk.ButtonsType{gtk.ButtonsNone, gtk.ButtonsOK, gtk.ButtonsClose, gtk.ButtonsCancel, gtk.ButtonsYesNo, gtk.ButtonsOKCancel}
+)
 
-func newAboutDialog() gtk.Widgetter {
-	return buttonAction("AboutDialog", "help-about", func() {
-		w := gtk.NewAboutDialog()
-		w.SetArtists([]string{"artists", "and", "others"})
-		w.SetAuthors([]string{"authors", "and", "others"})
-		w.SetComments("comments")
-		w.SetCopyright("copyright")
-		w.SetDocumenters([]string{"documenters", "and", "others"})
-		// w.SetLicense("license") // Overridden by SetLicenseType
-		// w.SetWrapLicense(true)
-		w.SetLicenseType(gtk.LicenseMITX11)
-		// w.SetLogo(gtk.NewImageFromIconName("document-new").Paintable()) // TODO: bug
-		w.SetLogoIconName("document-new")
-		w.SetProgramName("name")
-		w.SetSystemInformation("system Information")
-		w.SetTranslatorCredits("translator Credits")
-		w.SetVersion("version")
-		w.SetWebsite("website")
-		w.SetWebsiteLabel("website Label")
+// newMessageDialog has no AlertDialog counterpart: GtkAlertDialog is new in
+// GTK 4.10, and the gotk4 version in go.mod binds GTK 4.2. See the README,
+// Missing widgets.
+func newMessageDialog() gtk.Widgetter {
+	var typeNames, buttonsNames []string
+	for _, typ := range messageTypes {
+		typeNames = append(typeNames, typ.String())
+	}
+	for _, buttons := range buttonsTypes {
+		buttonsNames = append(buttonsNames, buttons.String())
+	}
+	dropType := gtk.NewDropDownFromStrings(typeNames)
+	dropButtons := gtk.NewDropDownFromStrings(buttonsNames)
+	dropButtons.SetSelected(1)
+	secondary := gtk.NewCheckButtonWithLabel("Secondary markup")
+	secondary.SetActive(true)
+	custom := gtk.NewCheckButtonWithLabel("Custom buttons")
+
+	open := func(typ gtk.MessageType, buttons gtk.ButtonsType) *gtk.MessageDialog {
+		w := NewMessageDialog(&gapp.Win.Window, typ, buttons, typ.String()+" message")
+		if secondary.Active() {
+			w.SetObjectProperty("secondary-text", "With <b>"+buttons.String()+"</b> buttons and <i>markup</i>.")
+			w.SetObjectProperty("secondary-use-markup", true)
+		}
+		if custom.Active() {
+			w.AddButton("_Retry", 1)
+			w.AddButton("_Ignore", 2)
+		}
 		w.Show()
+		return w
+	}
+
+	// One dialog, answered by a callback.
+	btnShow := gtk.NewButtonWithLabel("Show")
+	btnShow.Connect("clicked", func() {
+		w := open(messageTypes[dropType.Selected()], buttonsTypes[dropButtons.Selected()])
+		OnResponse(&w.Dialog, func(resp gtk.ResponseType) { events.Log("MessageDialog", "response", resp) })
+	})
+
+	// All combinations in turn: each dialog is opened by the response of the
+	// previous one, so all runs in the GTK main loop. Closing a dialog stops
+	// the tour.
+	btnAll := gtk.NewButtonWithLabel("Show all")
+	btnAll.Connect("clicked", func() {
+		var show func(i int)
+		show = func(i int) {
+			if i == len(messageTypes)*len(buttonsTypes) {
+				return
+			}
+			typ, buttons := messageTypes[i/len(buttonsTypes)], buttonsTypes[i%len(buttonsTypes)]
+			OnResponse(&open(typ, buttons).Dialog, func(resp gtk.ResponseType) {
+				events.Log("MessageDialog", "response", typ, buttons, resp)
+				if resp != gtk.ResponseDeleteEvent {
+					show(i + 1)
+				}
+			})
+		}
+		show(0)
 	})
+
+	grid := gtk.NewGrid()
+	grid.SetColumnSpacing(boxMargin)
+	grid.SetRowSpacing(boxMargin)
+	grid.Attach(gtk.NewLabel("Type"), 0, 0, 1, 1)
+	grid.Attach(dropType, 1, 0, 1, 1)
+	grid.Attach(gtk.NewLabel("Buttons"), 0, 1, 1, 1)
+	grid.Attach(dropButtons, 1, 1, 1, 1)
+	grid.Attach(secondary, 0, 2, 2, 1)
+	grid.Attach(custom, 0, 3, 2, 1)
+	return gtknew.VBox(boxMargin, grid, gtknew.HBox(boxMargin, btnShow, btnAll))
+}
+
+func newAboutDialog() gtk.Widgetter {
+	return buttonAction("AboutDialog", "help-about", showAbout)
+}
+
+// showAbout shows the about dialog, also used by the app.about action.
+func showAbout() {
+	w := gtk.NewAboutDialog()
+	w.SetTransientFor(&gapp.Win.Window)
+	w.SetArtists([]string{"artists", "and", "others"})
+	w.SetAuthors([]string{"authors", "and", "others"})
+	w.SetComments("comments")
+	w.SetCopyright("copyright")
+	w.SetDocumenters([]string{"documenters", "and", "others"})
+	// w.SetLicense("license") // Overridden by SetLicenseType
+	// w.SetWrapLicense(true)
+	w.SetLicenseType(gtk.LicenseMITX11)
+	// w.SetLogo(gtk.NewImageFromIconName("document-new").Paintable()) // TODO: bug
+	w.SetLogoIconName("document-new")
+	w.SetProgramName("name")
+	w.SetSystemInformation(versionsText())
+	w.SetTranslatorCredits("translator Credits")
+	w.SetVersion("version")
+	w.SetWebsite("website")
+	w.SetWebsiteLabel("website Label")
+	w.Show()
 }
 
 func newAssistant() gtk.Widgetter {
@@ -975,133 +1524,84 @@ func newAssistant() gtk.Widgetter {
 func newPrintPageSetupDialog() gtk.Widgetter {
 	return buttonAction("PageSetupDialog", "document-page-setup", func() {
 		gtk.PrintRunPageSetupDialogAsync(
-			gtk.NewWindow(),
-			gtk.NewPageSetup(),
-			gtk.NewPrintSettings(),
-			func(pageSetup *gtk.PageSetup) { fmt.Println("print page setup dialog closed") },
+			&gapp.Win.Window,
+			printing.PageSetup(),
+			printing.Settings(),
+			func(pageSetup *gtk.PageSetup) {
+				printing.setup = pageSetup // Used by the PrintDialog entry.
+				events.Log("PageSetupDialog", "done", pageSetup.PaperSize().DisplayName(), pageSetup.Orientation())
+			},
 		)
 	})
 }
 
 func newPrintDialog() gtk.Widgetter {
-	// return buttonAction("PrintDialog", "document-print", func() {}) // "printer"
-	return placeholder()
+	path := gtk.NewEntry()
+	path.SetText(filepath.Join(os.TempDir(), "gallery-report.pdf"))
+	path.SetHExpand(true)
+	status := gtk.NewLabel("")
+	status.SetWrap(true)
+
+	export := buttonAction("Export to PDF", "document-save", func() {
+		if e := ExportReport(path.Text()); e != nil {
+			status.SetText(e.Error())
+			return
+		}
+		status.SetText("Exported to " + path.Text())
+	})
+	print := buttonAction("Print", "document-print", func() {
+		if e := PrintReport(); e != nil {
+			status.SetText(e.Error())
+		}
+	})
+
+	return gtknew.VBox(boxMargin,
+		gtk.NewLabel("Gallery report: widgets list, inspected widget properties and a drawing."),
+		path,
+		gtknew.HBox(boxMargin, export, print),
+		status,
+	)
 }
 
 func newShortcutsWindow() gtk.Widgetter {
-	return buttonAction("ShortcutsWindow", "preferences-desktop-keyboard", func() {
-		b := buildhelp.NewFromFile("shortcuts-clocks.ui")
-		w := b.ShortcutsWindow("shortcuts-clocks")
-		testError(b.Errors())
-		w.Show()
-	})
+	return buttonAction("ShortcutsWindow", "preferences-desktop-keyboard", showShortcuts)
+}
+
+// showShortcuts shows the shortcuts window, also used by the app.shortcuts action.
+func showShortcuts() {
+	b := buildhelp.NewFromFile("shortcuts-clocks.ui")
+	w := b.ShortcutsWindow("shortcuts-clocks")
+	testError(b.Errors())
+	w.SetTransientFor(&gapp.Win.Window)
+	w.Show()
 }
 
 func newColorChooser() gtk.Widgetter {
 	w := gtk.NewColorChooserWidget()
-	w.Connect("color-activated", func() { col := w.RGBA(); fmt.Println("color chooser new color activated:", col.String()) })
+	w.Connect("color-activated", func() { col := w.RGBA(); events.Log("ColorChooser", "color-activated", col.String()) })
 	return gtknew.Expander("ColorChooser", w)
 }
 
 func newFileChooser() gtk.Widgetter {
 	w := gtk.NewFileChooserWidget(gtk.FileChooserActionOpen)
 	w.SetSelectMultiple(true)
-	w.Connect("up-folder", func() { fmt.Println("file chooser up-folder") })
-	w.Connect("down-folder", func() { fmt.Println("file chooser down-folder") })
+	w.Connect("up-folder", callLog("FileChooser", "up-folder"))
+	w.Connect("down-folder", callLog("FileChooser", "down-folder"))
 	return gtknew.Expander("FileChooser", w)
 }
 
 func newFontChooser() gtk.Widgetter {
 	w := gtk.NewFontChooserWidget()
-	w.Connect("font-activated", func() { fmt.Println("font chooser new font activated:", w.FontDesc()) })
+	w.Connect("font-activated", func() { events.Log("FontChooser", "font-activated", w.Font()) })
 	return gtknew.Expander("FontChooser", &w.Widget)
 }
 
 func newAppchooserDialog() gtk.Widgetter {
 	w := gtk.NewAppChooserWidget("video/avi")
-	w.Connect("application-selected", func() { fmt.Println("app chooser new application selected:", w.AppInfo().Name()) })
+	w.Connect("application-selected", func() { events.Log("AppchooserDialog", "application-selected", w.AppInfo().Name()) })
 	return gtknew.Expander("AppchooserDialog", &w.Widget)
 }
 
-//
-//-----------------------------------------------------------------[ CUSTOM WIDGET ]--
-
-// CustomWidget provides an example of custom widget that behaves like an
-// extended gtk.Box.
-//
-// It displays a timer and provides a switch that updates an icon and a text.
-type CustomWidget struct {
-	gtk.Box    // Extends the main container.
-	sw         *gtk.Switch
-	img        *gtk.Image
-	labelState *gtk.Label
-	labelTime  *gtk.Label
-}
-
-// NewCustomWidgetStarted creates and starts the custom widget.
-func NewCustomWidgetStarted() *CustomWidget {
-	w := NewCustomWidget()
-	go w.Loop()
-	return w
-}
-
-// NewCustomWidget creates an example widget with timer and switch.
-func NewCustomWidget() *CustomWidget {
-	box := &CustomWidget{
-		Box:        *gtknew.HBox(10),
-		sw:         gtk.NewSwitch(),
-		img:        gtk.NewImage(),
-		labelState: gtk.NewLabel("Custom Widget"),
-		labelTime:  gtk.NewLabel(""),
-	}
-
-	box.labelTime.SetHAlign(gtk.AlignEnd)
-	box.labelTime.SetHExpand(true)
-
-	box.sw.Connect("activate", func() { fmt.Println("custom activate", box.Active()) }) // signal "activate" doesn't seem to work
-	box.sw.Connect("state-set", box.switchToggled)
-	box.SetActive(true)
-
-	// Packing
-	box.Append(gtk.NewLabel("Custom Widget:"))
-	box.Append(box.sw)
-	box.Append(box.img)
-	box.Append(box.labelState)
-	box.Append(box.labelTime)
-	return box
-}
-
-// Widget Public API.
-
-// Active returns the switch value.
-func (w *CustomWidget) Active() bool { return w.sw.Active() }
-
-// SetActive sets the switch value.
-func (w *CustomWidget) SetActive(active bool) { w.sw.SetActive(active) }
-
-// Loop starts the timer loop.
-func (w *CustomWidget) Loop() {
-	// When called from a go routine, use IdleAdd to run your gtk actions within
-	// the gtk main loop.
-	for t := range time.Tick(time.Second) {
-		text := fmt.Sprintf("Last updated at %s.", t.Format(time.StampMilli))
-		gtknew.Idle(func() { w.labelTime.SetLabel(text) })
-	}
-}
-
-// Widget Private Callbacks.
-func (w *CustomWidget) switchToggled() {
-	// No need for IdleAdd as this is called from a gtk callback, when the switch
-	// is toggled, but also at widget creation during the window creation call
-	// with SetActive(true).
-	text := map[bool]string{false: "Active", true: "Inactive"}
-	icon := map[bool]string{false: "go-up", true: "go-down"}
-	newValue := !w.Active() // reverse value as this is called before the change.
-	w.img.SetFromIconName(icon[newValue])
-	w.labelState.SetLabel(text[newValue])
-	fmt.Println("custom widget switched to", newValue)
-}
-
 //
 //-----------------------------------------------------------------[ COMMON ]--
 
@@ -1132,50 +1632,8 @@ func buttonAction(label, icon string, call func()) gtk.Widgetter {
 	return w
 }
 
-func insertWithValues(model *gtk.ListStore, data map[int]interface{}) {
-	var keys []int
-	var values []externglib.Value
-	for k, v := range data {
-		keys = append(keys, k)
-		values = append(values, *externglib.NewValue(v))
-	}
-
-	model.InsertWithValues(-1, keys, values)
-}
-
-func downloadFile(url string) []byte {
-	resp, e := http.Get(url)
-	if e != nil {
-		fmt.Printf("can't find icon url (%s): %s", url, e)
-		return nil
-	}
-	byts, e := io.ReadAll(resp.Body)
-	resp.Body.Close()
-	if e != nil {
-		fmt.Printf("can't download icon (%s): %s", url, e)
-		return nil
-	}
-	return byts
-}
-
-func pixbufLoader(byts []byte) *gdkpixbuf.Pixbuf {
-	load := gdkpixbuf.NewPixbufLoader()
-	load.Connect("size-prepared", func(m *gdkpixbuf.PixbufLoader, w, h int) {
-		m.SetSize(48, 48)
-	})
-	load.Write(byts)
-	pix := load.Pixbuf()
-	e := load.Close()
-	if e != nil {
-		fmt.Printf("can't close pixbuf loader: %s", e)
-	}
-	return pix
-}
-
 func placeholder() gtk.Widgetter { return gtk.NewLabel("TODO") }
 
-func callPrint(args ...interface{}) func() { return func() { fmt.Println(args...) } }
-
 func testError(errs grun.Errors) {
 	if errs.IsError() {
 		fmt.Println(errs.Error())
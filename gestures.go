@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/cairo"
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+var listInput = Group{
+	{"GestureClick", newGestureClick, "mouse button press release double click"},
+	{"GestureDrag", newGestureDrag, "mouse pointer move offset drag"},
+	{"GestureZoom", newGestureZoom, "pinch scale touch touchpad"},
+	{"GestureRotate", newGestureRotate, "twist angle touch touchpad"},
+	{"GestureSwipe", newGestureSwipe, "fling velocity touch"},
+	{"GestureLongPress", newGestureLongPress, "hold touch context"},
+	{"EventControllerKey", newEventControllerKey, "keyboard keyval keycode shortcut"},
+	{"EventControllerMotion", newEventControllerMotion, "mouse pointer hover enter leave"},
+	{"EventControllerScroll", newEventControllerScroll, "wheel touchpad scroll delta"},
+}
+
+func newGestureClick() gtk.Widgetter {
+	c := newEventCanvas("GestureClick", "Click with any button")
+	click := gtk.NewGestureClick()
+	click.SetButton(0) // All buttons.
+	click.Connect("pressed", func(_ *gtk.GestureClick, n int, x, y float64) {
+		c.mark(x, y)
+		c.log(click.CurrentEventState(), "pressed", formatPoint(x, y), fmt.Sprintf("button=%d", click.CurrentButton()), fmt.Sprintf("n_press=%d", n))
+	})
+	click.Connect("released", func(_ *gtk.GestureClick, n int, x, y float64) {
+		c.log(click.CurrentEventState(), "released", formatPoint(x, y), fmt.Sprintf("button=%d", click.CurrentButton()), fmt.Sprintf("n_press=%d", n))
+	})
+	c.AddController(click)
+	return c.widget()
+}
+
+func newGestureDrag() gtk.Widgetter {
+	c := newEventCanvas("GestureDrag", "Drag to draw a line")
+	drag := gtk.NewGestureDrag()
+	drag.Connect("drag-begin", func(_ *gtk.GestureDrag, x, y float64) {
+		c.drag = [4]float64{x, y, x, y}
+		c.dragging = true
+		c.log(drag.CurrentEventState(), "drag-begin", formatPoint(x, y))
+	})
+	drag.Connect("drag-update", func(_ *gtk.GestureDrag, dx, dy float64) {
+		c.drag[2], c.drag[3] = c.drag[0]+dx, c.drag[1]+dy
+		c.log(drag.CurrentEventState(), "drag-update", formatOffset(dx, dy))
+	})
+	drag.Connect("drag-end", func(_ *gtk.GestureDrag, dx, dy float64) {
+		c.dragging = false
+		c.mark(c.drag[0]+dx, c.drag[1]+dy)
+		c.log(drag.CurrentEventState(), "drag-end", formatOffset(dx, dy))
+	})
+	c.AddController(drag)
+	return c.widget()
+}
+
+func newGestureZoom() gtk.Widgetter {
+	c := newEventCanvas("GestureZoom", "Pinch to scale the square")
+	c.square = true
+	zoom := gtk.NewGestureZoom()
+	zoom.Connect("scale-changed", func(_ *gtk.GestureZoom, scale float64) {
+		c.scale = scale
+		c.log(zoom.CurrentEventState(), "scale-changed", fmt.Sprintf("scale=%.2f", scale))
+	})
+	zoom.Connect("end", func() {
+		c.scale = 1
+		c.log(zoom.CurrentEventState(), "end")
+	})
+	c.AddController(zoom)
+	return c.widget()
+}
+
+func newGestureRotate() gtk.Widgetter {
+	c := newEventCanvas("GestureRotate", "Twist two fingers to rotate the square")
+	c.square = true
+	rotate := gtk.NewGestureRotate()
+	rotate.Connect("angle-changed", func(_ *gtk.GestureRotate, angle, delta float64) {
+		c.angle = delta
+		c.log(rotate.CurrentEventState(), "angle-changed", formatAngle("angle", angle), formatAngle("delta", delta))
+	})
+	rotate.Connect("end", func() {
+		c.angle = 0
+		c.log(rotate.CurrentEventState(), "end")
+	})
+	c.AddController(rotate)
+	return c.widget()
+}
+
+func newGestureSwipe() gtk.Widgetter {
+	c := newEventCanvas("GestureSwipe", "Drag and release quickly")
+	swipe := gtk.NewGestureSwipe()
+	swipe.Connect("swipe", func(_ *gtk.GestureSwipe, vx, vy float64) {
+		c.swipe = [2]float64{vx, vy}
+		c.log(swipe.CurrentEventState(), "swipe", fmt.Sprintf("velocity=(%.0f, %.0f) px/s", vx, vy))
+	})
+	c.AddController(swipe)
+	return c.widget()
+}
+
+func newGestureLongPress() gtk.Widgetter {
+	c := newEventCanvas("GestureLongPress", "Press and hold")
+	press := gtk.NewGestureLongPress()
+	press.Connect("pressed", func(_ *gtk.GestureLongPress, x, y float64) {
+		c.mark(x, y)
+		c.log(press.CurrentEventState(), "pressed", formatPoint(x, y))
+	})
+	press.Connect("cancelled", func() { c.log(press.CurrentEventState(), "cancelled") })
+	c.AddController(press)
+	return c.widget()
+}
+
+func newEventControllerKey() gtk.Widgetter {
+	c := newEventCanvas("EventControllerKey", "Click here, then type")
+
+	// Only the focus widget gets the key events: take it when clicked.
+	c.SetFocusable(true)
+	c.SetFocusOnClick(true)
+	c.Connect("notify::has-focus", c.QueueDraw)
+	click := gtk.NewGestureClick()
+	click.Connect("pressed", func() { c.GrabFocus() })
+	c.AddController(click)
+
+	key := gtk.NewEventControllerKey()
+	key.Connect("key-pressed", func(_ *gtk.EventControllerKey, keyval, keycode uint, state gdk.ModifierType) bool {
+		c.log(state, "key-pressed", "key="+gdk.KeyvalName(keyval), fmt.Sprintf("keycode=%d", keycode))
+		return false // Not handled: let the window shortcuts run.
+	})
+	key.Connect("key-released", func(_ *gtk.EventControllerKey, keyval, keycode uint, state gdk.ModifierType) {
+		c.log(state, "key-released", "key="+gdk.KeyvalName(keyval), fmt.Sprintf("keycode=%d", keycode))
+	})
+	key.Connect("modifiers", func(_ *gtk.EventControllerKey, state gdk.ModifierType) bool {
+		c.log(state, "modifiers")
+		return false
+	})
+	c.AddController(key)
+	return c.widget()
+}
+
+func newEventControllerMotion() gtk.Widgetter {
+	c := newEventCanvas("EventControllerMotion", "Move the pointer over here")
+	motion := gtk.NewEventControllerMotion()
+	motion.Connect("enter", func(_ *gtk.EventControllerMotion, x, y float64) {
+		c.log(motion.CurrentEventState(), "enter", formatPoint(x, y))
+	})
+	motion.Connect("motion", func(_ *gtk.EventControllerMotion, x, y float64) {
+		c.mark(x, y)
+		if time.Since(c.lastMotion) < motionLogInterval {
+			c.QueueDraw() // Draw every move, log some.
+			return
+		}
+		c.lastMotion = time.Now()
+		c.log(motion.CurrentEventState(), "motion", formatPoint(x, y))
+	})
+	motion.Connect("leave", func() { c.log(motion.CurrentEventState(), "leave") })
+	c.AddController(motion)
+	return c.widget()
+}
+
+func newEventControllerScroll() gtk.Widgetter {
+	c := newEventCanvas("EventControllerScroll", "Scroll to move the square")
+	c.square = true
+	scroll := gtk.NewEventControllerScroll(gtk.EventControllerScrollBothAxes)
+	scroll.Connect("scroll", func(_ *gtk.EventControllerScroll, dx, dy float64) bool {
+		c.offset[0] += dx * scrollStep
+		c.offset[1] += dy * scrollStep
+		c.log(scroll.CurrentEventState(), "scroll", formatOffset(dx, dy))
+		return true // Handled: don't scroll the page.
+	})
+	c.AddController(scroll)
+	return c.widget()
+}
+
+//
+//------------------------------------------------------------[ EVENT CANVAS ]--
+
+const (
+	canvasTrail       = 20                     // Number of pointer positions drawn.
+	motionLogInterval = 100 * time.Millisecond // Motion events are many: log some.
+	scrollStep        = 10                     // Square move by scroll unit, in pixels.
+)
+
+// eventCanvas is a DrawingArea showing the events of its controllers: the last
+// pointer positions, the drag line, the swipe velocity and a square following
+// zoom, rotation and scroll. The last event is written at the bottom.
+type eventCanvas struct {
+	*gtk.DrawingArea
+	name string // Event log entry.
+	hint string // Displayed until the first event.
+
+	trail    [][2]float64 // Last pointer positions, oldest first.
+	drag     [4]float64   // Drag start and end.
+	dragging bool
+	swipe    [2]float64 // Last swipe velocity.
+	square   bool       // Draw the square.
+	scale    float64    // Square zoom.
+	angle    float64    // Square rotation, in radians.
+	offset   [2]float64 // Square scroll offset.
+	text     string     // Last event, decoded.
+
+	lastMotion time.Time
+}
+
+func newEventCanvas(name, hint string) *eventCanvas {
+	c := &eventCanvas{DrawingArea: gtk.NewDrawingArea(), name: name, hint: hint, scale: 1}
+	c.SetContentWidth(300)
+	c.SetContentHeight(180)
+	c.SetDrawFunc(func(_ *gtk.DrawingArea, cr *cairo.Context, width, height int) {
+		c.draw(cr, float64(width), float64(height))
+	})
+	return c
+}
+
+// widget packs the canvas like the other gallery widgets.
+func (c *eventCanvas) widget() gtk.Widgetter { return gtknew.VBox(boxMargin, c.DrawingArea) }
+
+// mark adds a pointer position to the trail.
+func (c *eventCanvas) mark(x, y float64) {
+	c.trail = append(c.trail, [2]float64{x, y})
+	if len(c.trail) > canvasTrail {
+		c.trail = c.trail[1:]
+	}
+}
+
+// log sends the event to the event log, with the decoded modifiers, and shows
+// it on the canvas.
+func (c *eventCanvas) log(state gdk.ModifierType, signal string, args ...interface{}) {
+	args = append(args, "mods="+modifierNames(state))
+	events.Log(c.name, signal, args...)
+	c.text = signal + " " + strings.TrimSpace(fmt.Sprintln(args...))
+	c.QueueDraw()
+}
+
+func (c *eventCanvas) draw(cr *cairo.Context, w, h float64) {
+	cr.SetSourceRGB(0.96, 0.96, 0.96)
+	cr.Rectangle(0, 0, w, h)
+	cr.Fill()
+	if c.HasFocus() {
+		cr.SetSourceRGB(0.2, 0.4, 0.9)
+		cr.SetLineWidth(4)
+		cr.Rectangle(0, 0, w, h)
+		cr.Stroke()
+	}
+
+	// Square: zoom, rotation and scroll.
+	if c.square {
+		cr.Save()
+		cr.Translate(w/2+c.offset[0], h/2+c.offset[1])
+		cr.Rotate(c.angle)
+		cr.Scale(c.scale, c.scale)
+		cr.SetSourceRGBA(0.2, 0.6, 0.3, 0.6)
+		cr.Rectangle(-25, -25, 50, 50)
+		cr.Fill()
+		cr.Restore()
+	}
+
+	// Pointer trail, fading.
+	for i, p := range c.trail {
+		cr.SetSourceRGBA(0.9, 0.2, 0.2, float64(i+1)/float64(len(c.trail)))
+		cr.Arc(p[0], p[1], 5, 0, 2*math.Pi)
+		cr.Fill()
+	}
+
+	// Drag line, from the start point.
+	if c.dragging {
+		cr.SetSourceRGB(0.2, 0.2, 0.8)
+		cr.SetLineWidth(2)
+		cr.Arc(c.drag[0], c.drag[1], 4, 0, 2*math.Pi)
+		cr.Fill()
+		cr.MoveTo(c.drag[0], c.drag[1])
+		cr.LineTo(c.drag[2], c.drag[3])
+		cr.Stroke()
+	}
+
+	// Swipe velocity, from the center: 1 pixel for 10 px/s.
+	if c.swipe != [2]float64{} {
+		cr.SetSourceRGB(0.8, 0.5, 0.1)
+		cr.SetLineWidth(3)
+		cr.MoveTo(w/2, h/2)
+		cr.LineTo(w/2+c.swipe[0]/10, h/2+c.swipe[1]/10)
+		cr.Stroke()
+	}
+
+	text := c.text
+	if text == "" {
+		text = c.hint
+	}
+	cr.SetSourceRGB(0.2, 0.2, 0.2)
+	cr.SetFontSize(12)
+	cr.MoveTo(8, h-8)
+	cr.ShowText(text)
+}
+
+// modifierNames decodes the modifiers state, like "Shift+Control".
+func modifierNames(state gdk.ModifierType) string {
+	var names []string
+	for _, mod := range []struct {
+		Mask gdk.ModifierType
+		Name string
+	}{
+		{gdk.ShiftMask, "Shift"},
+		{gdk.LockMask, "Lock"},
+		{gdk.ControlMask, "Control"},
+		{gdk.AltMask, "Alt"},
+		{gdk.SuperMask, "Super"},
+		{gdk.HyperMask, "Hyper"},
+		{gdk.MetaMask, "Meta"},
+		{gdk.Button1Mask, "Button1"},
+		{gdk.Button2Mask, "Button2"},
+		{gdk.Button3Mask, "Button3"},
+		{gdk.Button4Mask, "Button4"},
+		{gdk.Button5Mask, "Button5"},
+	} {
+		if state.Has(mod.Mask) {
+			names = append(names, mod.Name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "+")
+}
+
+func formatPoint(x, y float64) string  { return fmt.Sprintf("x=%.0f y=%.0f", x, y) }
+func formatOffset(x, y float64) string { return fmt.Sprintf("dx=%.1f dy=%.1f", x, y) }
+
+func formatAngle(name string, rad float64) string {
+	return fmt.Sprintf("%s=%.0f°", name, rad*180/math.Pi)
+}
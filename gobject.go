@@ -0,0 +1,190 @@
+package main
+
+// #cgo pkg-config: gtk4
+// #include <stdlib.h>
+// #include <gtk/gtk.h>
+//
+// static GParamSpec **gallery_list_properties(guintptr obj, guint *n) {
+// 	return g_object_class_list_properties(G_OBJECT_GET_CLASS((GObject *)obj), n);
+// }
+//
+// static GParamSpec *gallery_pspec_at(GParamSpec **list, guint i) { return list[i]; }
+//
+// static gboolean gallery_pspec_range(GParamSpec *p, gdouble *min, gdouble *max) {
+// 	if (G_IS_PARAM_SPEC_INT(p))    { *min = G_PARAM_SPEC_INT(p)->minimum;    *max = G_PARAM_SPEC_INT(p)->maximum;    return TRUE; }
+// 	if (G_IS_PARAM_SPEC_UINT(p))   { *min = G_PARAM_SPEC_UINT(p)->minimum;   *max = G_PARAM_SPEC_UINT(p)->maximum;   return TRUE; }
+// 	if (G_IS_PARAM_SPEC_LONG(p))   { *min = G_PARAM_SPEC_LONG(p)->minimum;   *max = G_PARAM_SPEC_LONG(p)->maximum;   return TRUE; }
+// 	if (G_IS_PARAM_SPEC_ULONG(p))  { *min = G_PARAM_SPEC_ULONG(p)->minimum;  *max = G_PARAM_SPEC_ULONG(p)->maximum;  return TRUE; }
+// 	if (G_IS_PARAM_SPEC_INT64(p))  { *min = G_PARAM_SPEC_INT64(p)->minimum;  *max = G_PARAM_SPEC_INT64(p)->maximum;  return TRUE; }
+// 	if (G_IS_PARAM_SPEC_UINT64(p)) { *min = G_PARAM_SPEC_UINT64(p)->minimum; *max = G_PARAM_SPEC_UINT64(p)->maximum; return TRUE; }
+// 	if (G_IS_PARAM_SPEC_FLOAT(p))  { *min = G_PARAM_SPEC_FLOAT(p)->minimum;  *max = G_PARAM_SPEC_FLOAT(p)->maximum;  return TRUE; }
+// 	if (G_IS_PARAM_SPEC_DOUBLE(p)) { *min = G_PARAM_SPEC_DOUBLE(p)->minimum; *max = G_PARAM_SPEC_DOUBLE(p)->maximum; return TRUE; }
+// 	return FALSE;
+// }
+//
+// static GEnumValue *gallery_enum_value(GType t, guint i) {
+// 	GEnumClass *class = g_type_class_ref(t); // Kept referenced, enum classes are static anyway.
+// 	return i < class->n_values ? &class->values[i] : NULL;
+// }
+//
+// static void gallery_set_enum(guintptr obj, const gchar *name, gint value) {
+// 	g_object_set((GObject *)obj, name, value, NULL);
+// }
+//
+// static void gallery_set_rgba(guintptr obj, const gchar *name, const gchar *spec) {
+// 	GdkRGBA rgba;
+// 	if (gdk_rgba_parse(&rgba, spec)) {
+// 		g_object_set((GObject *)obj, name, &rgba, NULL);
+// 	}
+// }
+//
+// static gchar *gallery_get_rgba(guintptr obj, const gchar *name) {
+// 	GdkRGBA *rgba = NULL;
+// 	g_object_get((GObject *)obj, name, &rgba, NULL);
+// 	if (rgba == NULL) {
+// 		return NULL;
+// 	}
+// 	gchar *spec = gdk_rgba_to_string(rgba);
+// 	gdk_rgba_free(rgba);
+// 	return spec;
+// }
+//
+// static void gallery_bind_property(guintptr source, const gchar *source_prop, guintptr target, const gchar *target_prop, GBindingFlags flags) {
+// 	g_object_bind_property((GObject *)source, source_prop, (GObject *)target, target_prop, flags);
+// }
+import "C"
+
+import (
+	"runtime"
+	"runtime/cgo"
+	"unsafe"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+)
+
+//
+//-------------------------------------------------------[ OBJECT PROPERTIES ]--
+
+// ParamSpec describes a GObject property.
+type ParamSpec struct {
+	Name      string
+	Nick      string
+	Blurb     string
+	Type      externglib.Type
+	Readable  bool
+	Writable  bool // False for read-only and construct-only properties.
+	HasRange  bool // Min and Max are set for numeric properties.
+	Min, Max  float64
+	EnumNicks []string // Enum values nicks, for enum properties.
+	EnumInts  []int    // Enum values, in the same order as EnumNicks.
+}
+
+// ListProperties lists all properties of the object class.
+func ListProperties(obj *externglib.Object) []ParamSpec {
+	var n C.guint
+	list := C.gallery_list_properties(C.guintptr(obj.Native()), &n)
+	defer C.g_free(C.gpointer(list))
+
+	specs := make([]ParamSpec, 0, int(n))
+	for i := C.guint(0); i < n; i++ {
+		p := C.gallery_pspec_at(list, i)
+		spec := ParamSpec{
+			Name:     goString(C.g_param_spec_get_name(p)),
+			Nick:     goString(C.g_param_spec_get_nick(p)),
+			Blurb:    goString(C.g_param_spec_get_blurb(p)),
+			Type:     externglib.Type(p.value_type),
+			Readable: p.flags&C.G_PARAM_READABLE != 0,
+			Writable: p.flags&C.G_PARAM_WRITABLE != 0 && p.flags&C.G_PARAM_CONSTRUCT_ONLY == 0,
+		}
+
+		var min, max C.gdouble
+		if C.gallery_pspec_range(p, &min, &max) != 0 {
+			spec.HasRange, spec.Min, spec.Max = true, float64(min), float64(max)
+		}
+
+		if externglib.FundamentalType(spec.Type) == externglib.TypeEnum {
+			for j := C.guint(0); ; j++ {
+				val := C.gallery_enum_value(p.value_type, j)
+				if val == nil {
+					break
+				}
+				spec.EnumNicks = append(spec.EnumNicks, goString(val.value_nick))
+				spec.EnumInts = append(spec.EnumInts, int(val.value))
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// SetEnumProperty sets an enum property from its int value.
+func SetEnumProperty(obj *externglib.Object, name string, value int) {
+	cname := cString(name)
+	defer C.free(unsafe.Pointer(cname))
+	C.gallery_set_enum(C.guintptr(obj.Native()), cname, C.gint(value))
+}
+
+// SetRGBAProperty sets a GdkRGBA property from a color spec like "#ff0000" or
+// "rgba(255,0,0,0.5)".
+func SetRGBAProperty(obj *externglib.Object, name, spec string) {
+	cname := cString(name)
+	cspec := cString(spec)
+	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cspec))
+	C.gallery_set_rgba(C.guintptr(obj.Native()), cname, cspec)
+}
+
+// RGBAProperty returns a GdkRGBA property as a color spec, or false if unset.
+func RGBAProperty(obj *externglib.Object, name string) (string, bool) {
+	cname := cString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cspec := C.gallery_get_rgba(C.guintptr(obj.Native()), cname)
+	if cspec == nil {
+		return "", false
+	}
+	defer C.g_free(C.gpointer(cspec))
+	return goString(cspec), true
+}
+
+// BindingFlags sets the direction and conversion of a property binding.
+type BindingFlags int
+
+// Binding flags, see GBindingFlags.
+const (
+	BindingDefault       BindingFlags = C.G_BINDING_DEFAULT
+	BindingBidirectional BindingFlags = C.G_BINDING_BIDIRECTIONAL
+	BindingSyncCreate    BindingFlags = C.G_BINDING_SYNC_CREATE // Set the target value at creation.
+	BindingInvertBoolean BindingFlags = C.G_BINDING_INVERT_BOOLEAN
+)
+
+// BindProperty keeps the target property in sync with the source property, and
+// both ways with BindingBidirectional. The binding lasts as long as both
+// objects.
+func BindProperty(source externglib.Objector, sourceProp string, target externglib.Objector, targetProp string, flags BindingFlags) {
+	csource := cString(sourceProp)
+	ctarget := cString(targetProp)
+	defer C.free(unsafe.Pointer(csource))
+	defer C.free(unsafe.Pointer(ctarget))
+	C.gallery_bind_property(C.guintptr(source.Native()), csource, C.guintptr(target.Native()), ctarget, C.GBindingFlags(flags))
+	runtime.KeepAlive(source)
+	runtime.KeepAlive(target)
+}
+
+// NewStringObject creates a GtkStringObject, a GObject holding a string in
+// its "string" property, usable as list model item.
+func NewStringObject(str string) *externglib.Object {
+	cstr := cString(str)
+	defer C.free(unsafe.Pointer(cstr))
+	return externglib.AssumeOwnership(unsafe.Pointer(C.gtk_string_object_new(cstr)))
+}
+
+// CompareDataPointer returns the item pointer given to a glib.CompareDataFunc,
+// like the items compared by a gtk.CustomSorter.
+// gotk4 types the gconstpointer arguments as cgo.Handle, but converts the C
+// pointers as is: they're not handles, and Value would panic. They're the raw
+// GObject pointers, to compare with Native.
+func CompareDataPointer(h cgo.Handle) uintptr { return uintptr(h) }
+
+func goString(str *C.gchar) string { return C.GoString((*C.char)(unsafe.Pointer(str))) }
+
+func cString(str string) *C.gchar { return (*C.gchar)(unsafe.Pointer(C.CString(str))) }
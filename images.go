@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/gdkpixbuf/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/gallery/assets"
+	"github.com/gtkool4/gallery/pixbufio"
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//------------------------------------------------------------------[ IMAGES ]--
+
+const imageSource = "https://raw.githubusercontent.com/golang-samples/gopher-vector/master/"
+
+// assetsRemoteEnv enables the download of remote images, when set to any value.
+const assetsRemoteEnv = "GALLERY_REMOTE_ASSETS"
+
+// loader loads the gallery images, bundled or remote when requested.
+var loader = newAssetsLoader()
+
+// loaderCtx is canceled when the application stops, to abort pending loads.
+var loaderCtx, loaderCancel = context.WithCancel(context.Background())
+
+func newAssetsLoader() *assets.Loader {
+	l := assets.NewLoader(map[string]string{
+		"gotk4.png":        "https://avatars.githubusercontent.com/u/13782055?s=200&v=4",
+		"gopher-front.png": imageSource + "gopher-front.png",
+		"gopher-side.png":  imageSource + "gopher-side_color.png",
+		"gopher.png":       imageSource + "gopher.png",
+	})
+	l.Remote = os.Getenv(assetsRemoteEnv) != ""
+	l.CacheDir = assets.DefaultCacheDir("gtkool4-gallery")
+	l.Timeout = 30 * time.Second
+	return l
+}
+
+// loadPixbuf loads an image in background and calls ready with the pixbuf in
+// the GTK main loop. progress can be nil.
+//
+// The load is canceled when the widget is destroyed.
+func loadPixbuf(w gtk.Widgetter, key string, progress func(assets.Progress), ready func(*gdkpixbuf.Pixbuf)) {
+	ctx, cancel := context.WithCancel(loaderCtx)
+	w.Connect("destroy", cancel)
+
+	var onProgress func(assets.Progress)
+	if progress != nil {
+		onProgress = func(p assets.Progress) { gtknew.Idle(func() { progress(p) }) }
+	}
+
+	loader.LoadAsync(ctx, key, onProgress, func(byts []byte, e error) {
+		cancel()
+		gtknew.Idle(func() {
+			if e != nil {
+				events.Log("Assets", "error", key, e)
+			}
+			if byts == nil {
+				return
+			}
+			pix, e := pixbufio.Decode(bytes.NewReader(byts), pixbufio.SizeFit, 48, 48)
+			if e != nil {
+				events.Log("Assets", "error", key, e)
+			}
+			if pix != nil {
+				ready(pix)
+			}
+		})
+	})
+}
+
+// placeholderPixbuf creates a grey square, displayed while an image loads.
+func placeholderPixbuf() *gdkpixbuf.Pixbuf {
+	pix := gdkpixbuf.NewPixbuf(gdkpixbuf.ColorspaceRGB, true, 8, 48, 48)
+	pix.Fill(0x80808040)
+	return pix
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//---------------------------------------------------------------[ INSPECTOR ]--
+
+// inspector shows the properties of the selected gallery widget.
+// Set when the gallery is created.
+var inspector *Inspector
+
+// Inspector is a side panel to view and edit live the properties of a widget.
+type Inspector struct {
+	gtk.Box
+	title  *gtk.Label
+	scroll *gtk.ScrolledWindow
+
+	name   string             // Entry name of the inspected widget.
+	target *externglib.Object // Inspected widget, nil before the first click.
+}
+
+// NewInspector creates an empty, hidden inspector panel.
+func NewInspector() *Inspector {
+	insp := &Inspector{
+		Box:    *gtknew.VBox(boxMargin),
+		title:  gtk.NewLabel(""),
+		scroll: gtk.NewScrolledWindow(),
+	}
+	insp.title.SetHExpand(true)
+	insp.scroll.SetVExpand(true)
+	insp.scroll.SetSizeRequest(320, -1)
+
+	close := gtk.NewButtonFromIconName("window-close")
+	close.SetTooltipText("Close the inspector")
+	close.Connect("clicked", func() { insp.SetVisible(false) })
+
+	insp.Append(gtknew.HBox(boxMargin, insp.title, close))
+	insp.Append(insp.scroll)
+	insp.SetVisible(false)
+	return insp
+}
+
+// Inspect shows the properties of the widget made for the named entry.
+func (insp *Inspector) Inspect(name string, w gtk.Widgetter) {
+	w = inspectTarget(w)
+	obj := externglib.InternObject(w)
+	insp.name, insp.target = name, obj
+	insp.title.SetMarkup(fmt.Sprintf("<b>%s</b> %s", name, obj.TypeFromInstance().Name()))
+
+	grid := gtk.NewGrid()
+	grid.SetColumnSpacing(6)
+	grid.SetRowSpacing(2)
+	row := 0
+	for _, spec := range ListProperties(obj) {
+		if !spec.Readable {
+			continue
+		}
+		label := gtk.NewLabel(spec.Name)
+		label.SetHAlign(gtk.AlignStart)
+		label.SetTooltipText(fmt.Sprintf("%s (%s)\n%s", spec.Nick, spec.Type.Name(), spec.Blurb))
+		editor := newPropertyEditor(obj, spec)
+		editor.SetHExpand(true)
+		grid.Attach(label, 0, row, 1, 1)
+		grid.Attach(editor, 1, row, 1, 1)
+		row++
+	}
+	insp.scroll.SetChild(grid)
+	insp.SetVisible(true)
+}
+
+// Target returns the inspected widget and its entry name, or nil if none.
+func (insp *Inspector) Target() (string, *externglib.Object) { return insp.name, insp.target }
+
+// inspectTarget finds the demonstrated widget in the ones wrapped in a single
+// child box, like gtknew.VBox(boxMargin, w).
+func inspectTarget(w gtk.Widgetter) gtk.Widgetter {
+	typeBox := externglib.TypeFromName("GtkBox")
+	for externglib.InternObject(w).TypeFromInstance() == typeBox {
+		child := w.FirstChild()
+		if child == nil || child.NextSibling() != nil {
+			break
+		}
+		w = child
+	}
+	return w
+}
+
+// newPropertyEditor creates an editor matching the property type, applying
+// changes live.
+func newPropertyEditor(obj *externglib.Object, spec ParamSpec) gtk.Widgetter {
+	typ := externglib.FundamentalType(spec.Type)
+	var editor gtk.Widgetter
+	switch {
+	case typ == externglib.TypeBoolean:
+		sw := gtk.NewSwitch()
+		sw.SetActive(obj.ObjectProperty(spec.Name) == true)
+		sw.SetHAlign(gtk.AlignStart)
+		sw.Connect("notify::active", func() { obj.SetObjectProperty(spec.Name, sw.Active()) })
+		editor = sw
+
+	case isNumberType(typ):
+		min, max := -1e6, 1e6
+		if spec.HasRange {
+			min, max = math.Max(spec.Min, min), math.Min(spec.Max, max)
+		}
+		step, digits := 1.0, uint(0)
+		if typ == externglib.TypeFloat || typ == externglib.TypeDouble {
+			step, digits = math.Min(0.1, (max-min)/100), 2
+		}
+		spin := gtk.NewSpinButtonWithRange(min, max, step)
+		spin.SetDigits(digits)
+		spin.SetValue(toFloat(obj.ObjectProperty(spec.Name)))
+		spin.Connect("value-changed", func() { obj.SetObjectProperty(spec.Name, numberValue(typ, spin.Value())) })
+		editor = spin
+
+	case typ == externglib.TypeString:
+		entry := gtk.NewEntry()
+		if str, ok := obj.ObjectProperty(spec.Name).(string); ok {
+			entry.SetText(str)
+		}
+		entry.Connect("changed", func() { obj.SetObjectProperty(spec.Name, entry.Text()) })
+		editor = entry
+
+	case typ == externglib.TypeEnum:
+		drop := gtk.NewDropDownFromStrings(spec.EnumNicks)
+		current := int(toFloat(obj.ObjectProperty(spec.Name)))
+		for i, value := range spec.EnumInts {
+			if value == current {
+				drop.SetSelected(uint(i))
+			}
+		}
+		drop.Connect("notify::selected", func() {
+			if i := int(drop.Selected()); i < len(spec.EnumInts) {
+				SetEnumProperty(obj, spec.Name, spec.EnumInts[i])
+			}
+		})
+		editor = drop
+
+	case spec.Type.Name() == "GdkRGBA":
+		color := gdk.NewRGBA(0, 0, 0, 1)
+		if str, ok := RGBAProperty(obj, spec.Name); ok {
+			color.Parse(str)
+		}
+		btn := gtk.NewColorButtonWithRGBA(&color)
+		btn.SetHAlign(gtk.AlignStart)
+		btn.Connect("color-set", func() { col := btn.RGBA(); SetRGBAProperty(obj, spec.Name, col.String()) })
+		editor = btn
+
+	default:
+		return gtknew.LabelWithMarkup("<i>" + spec.Type.Name() + "</i>")
+	}
+
+	editor.SetSensitive(spec.Writable)
+	return editor
+}
+
+func isNumberType(typ externglib.Type) bool {
+	switch typ {
+	case externglib.TypeInt, externglib.TypeUint, externglib.TypeLong, externglib.TypeUlong,
+		externglib.TypeInt64, externglib.TypeUint64, externglib.TypeFloat, externglib.TypeDouble:
+		return true
+	}
+	return false
+}
+
+// numberValue converts the value to the Go type matching the GType, so the
+// property is set without a GValue transformation.
+func numberValue(typ externglib.Type, v float64) interface{} {
+	switch typ {
+	case externglib.TypeInt:
+		return int(v)
+	case externglib.TypeUint:
+		return uint(v)
+	case externglib.TypeLong:
+		return int32(v) // Mapped to glong by externglib.NewValue.
+	case externglib.TypeUlong:
+		return uint32(v)
+	case externglib.TypeInt64:
+		return int64(v)
+	case externglib.TypeUint64:
+		return uint64(v)
+	case externglib.TypeFloat:
+		return float32(v)
+	}
+	return v
+}
+
+// toFloat converts a numeric property value to float64. Enum values are
+// returned by their own Go type, like gtk.Align, so they are converted too.
+func toFloat(v interface{}) float64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//--------------------------------------------------------------[ LAZY STACK ]--
+
+// LazyStack is a gtk.Stack with pages created only the first time they are
+// shown, so a large catalog starts fast and only uses what was visited.
+type LazyStack struct {
+	*gtk.Stack
+	pages map[string]*lazyPage
+}
+
+type lazyPage struct {
+	box    *gtk.Box             // Empty container, filled when first shown.
+	create func() gtk.Widgetter // Nil once the page has been created.
+}
+
+// NewLazyStack creates an empty stack of lazy pages.
+func NewLazyStack() *LazyStack {
+	stack := &LazyStack{
+		Stack: gtk.NewStack(),
+		pages: make(map[string]*lazyPage),
+	}
+	stack.SetHExpand(true)
+	stack.SetVExpand(true)
+	stack.SetTransitionType(gtk.StackTransitionTypeCrossfade)
+	stack.Connect("notify::visible-child", func() { stack.Build(stack.VisibleChildName()) })
+	return stack
+}
+
+// AddLazy adds a titled page, created with the create func when first shown.
+func (s *LazyStack) AddLazy(name, title string, create func() gtk.Widgetter) {
+	page := &lazyPage{
+		box:    gtknew.VBox(0),
+		create: create,
+	}
+	s.pages[name] = page
+	s.AddTitled(page.box, name, title)
+	if s.VisibleChildName() == name { // The first page is visible as soon as added.
+		s.Build(name)
+	}
+}
+
+// Build creates the named page content if it wasn't already.
+func (s *LazyStack) Build(name string) {
+	page, ok := s.pages[name]
+	if !ok || page.create == nil {
+		return
+	}
+	w := page.create()
+	page.create = nil
+	w.SetHExpand(true)
+	w.SetVExpand(true)
+	page.box.Append(w)
+}
+
+// SetPageVisible shows or hides the named page, and its sidebar entry.
+func (s *LazyStack) SetPageVisible(name string, visible bool) {
+	if page, ok := s.pages[name]; ok {
+		s.Page(page.box).SetVisible(visible)
+	}
+}
+
+// WithSidebar packs the stack with a gtk.StackSidebar to browse its pages.
+func (s *LazyStack) WithSidebar() gtk.Widgetter {
+	sidebar := gtk.NewStackSidebar()
+	sidebar.SetStack(s.Stack)
+	return gtknew.HBox(0, sidebar, gtknew.VSep(), s)
+}
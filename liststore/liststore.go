@@ -0,0 +1,200 @@
+// Package liststore declares a gtk.ListStore from a Go struct, and reads or
+// writes its rows as structs.
+//
+// Each exported field is a column, in declaration order. The column type is
+// the GType matching the field Go type (string, bool, numbers). Other types
+// need a gtype tag, and fields are skipped with gtype:"-":
+//
+//	type Item struct {
+//		Ref   string
+//		Text  string
+//		Icon  *gdkpixbuf.Pixbuf `gtype:"GdkPixbuf"`
+//		cache int               // Unexported: not a column.
+//	}
+//
+//	store := liststore.New(Item{})
+//	store.Append(Item{Ref: "0", Text: "hello"})
+//	store.Bind(column, cell, "markup", "Text")
+//
+// Declaration and row type errors are programming errors: they panic.
+package liststore
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+)
+
+// Store is a gtk.ListStore with columns declared by a struct.
+type Store struct {
+	*gtk.ListStore
+
+	rowType reflect.Type
+	fields  []int             // Struct field index, by column.
+	types   []externglib.Type // GType, by column.
+	columns map[string]int    // Column, by field name.
+}
+
+// CellLayout maps model columns to cell renderer attributes, like
+// gtk.TreeViewColumn or gtk.ComboBox.
+type CellLayout interface {
+	AddAttribute(cell gtk.CellRendererer, attribute string, column int)
+}
+
+// New creates a store with a column for each exported field of row, a struct
+// or a pointer to a struct.
+func New(row interface{}) *Store {
+	typ := reflect.TypeOf(row)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("liststore: row must be a struct, got %T", row))
+	}
+
+	s := &Store{rowType: typ, columns: make(map[string]int)}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("gtype")
+		if field.PkgPath != "" || tag == "-" { // Unexported or skipped.
+			continue
+		}
+		gtype, e := fieldType(field.Type, tag)
+		if e != nil {
+			panic(fmt.Sprintf("liststore: field %s.%s: %v", typ.Name(), field.Name, e))
+		}
+		s.columns[field.Name] = len(s.fields)
+		s.fields = append(s.fields, i)
+		s.types = append(s.types, gtype)
+	}
+	s.ListStore = gtk.NewListStore(s.types)
+	return s
+}
+
+// fieldType returns the GType of a column, named by the tag or matching the
+// Go type.
+func fieldType(typ reflect.Type, tag string) (externglib.Type, error) {
+	if tag != "" {
+		gtype := externglib.TypeFromName(tag)
+		if gtype == externglib.TypeInvalid {
+			return gtype, fmt.Errorf("unknown GType %q", tag)
+		}
+		return gtype, nil
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		// Same conversion as the values, so they always match.
+		return externglib.NewValue(reflect.Zero(typ).Interface()).Type(), nil
+	}
+	return externglib.TypeInvalid, fmt.Errorf("type %s needs a gtype tag", typ)
+}
+
+// Column returns the column of the field.
+func (s *Store) Column(field string) int {
+	col, ok := s.columns[field]
+	if !ok {
+		panic(fmt.Sprintf("liststore: no column for field %s.%s", s.rowType.Name(), field))
+	}
+	return col
+}
+
+// Bind sets the cell attribute to the column of the field.
+func (s *Store) Bind(layout CellLayout, cell gtk.CellRendererer, attribute, field string) {
+	layout.AddAttribute(cell, attribute, s.Column(field))
+}
+
+// Append adds a row at the end of the store.
+func (s *Store) Append(row interface{}) gtk.TreeIter {
+	iter := s.ListStore.Append()
+	s.Set(&iter, row)
+	return iter
+}
+
+// Set updates all columns of the row.
+func (s *Store) Set(iter *gtk.TreeIter, row interface{}) {
+	rv := s.rowValue(row)
+	columns := make([]int, len(s.fields))
+	values := make([]externglib.Value, len(s.fields))
+	for col, i := range s.fields {
+		columns[col] = col
+		values[col] = *s.value(col, rv.Field(i))
+	}
+	s.ListStore.Set(iter, columns, values)
+}
+
+// SetField updates the column of one field.
+func (s *Store) SetField(iter *gtk.TreeIter, field string, value interface{}) {
+	col := s.Column(field)
+	v := reflect.ValueOf(value)
+	if typ := s.rowType.Field(s.fields[col]).Type; v.IsValid() && v.Type() != typ && v.Type().ConvertibleTo(typ) {
+		v = v.Convert(typ) // Like 1 for a float64 column.
+	}
+	s.ListStore.SetValue(iter, col, s.value(col, v))
+}
+
+// Get reads the row into the struct pointed by row.
+func (s *Store) Get(iter *gtk.TreeIter, row interface{}) {
+	rv := reflect.ValueOf(row)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Type() != s.rowType {
+		panic(fmt.Sprintf("liststore: Get needs a *%s, got %T", s.rowType.Name(), row))
+	}
+	rv = rv.Elem()
+	for col, i := range s.fields {
+		field := rv.Field(i)
+		value := s.ListStore.Value(iter, col)
+		goval := value.GoValue()
+		if goval == nil || goval == externglib.InvalidValue {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		val := reflect.ValueOf(goval)
+		if obj, ok := goval.(externglib.Objector); val.Kind() == reflect.Ptr && val.IsNil() || ok && externglib.InternObject(obj) == nil {
+			field.Set(reflect.Zero(field.Type())) // NULL object, or wrapped NULL object.
+			continue
+		}
+		if !val.Type().ConvertibleTo(field.Type()) {
+			panic(fmt.Sprintf("liststore: column %d: can't convert %s to %s", col, val.Type(), field.Type()))
+		}
+		field.Set(val.Convert(field.Type()))
+	}
+}
+
+// Rows reads all rows, in a slice of the row struct type (as interface{}).
+func (s *Store) Rows() interface{} {
+	rows := reflect.MakeSlice(reflect.SliceOf(s.rowType), 0, 0)
+	s.ListStore.Foreach(func(_ gtk.TreeModeller, _ *gtk.TreePath, iter *gtk.TreeIter) bool {
+		row := reflect.New(s.rowType)
+		s.Get(iter, row.Interface())
+		rows = reflect.Append(rows, row.Elem())
+		return false
+	})
+	return rows.Interface()
+}
+
+// rowValue returns the struct value of row, checking its type.
+func (s *Store) rowValue(row interface{}) reflect.Value {
+	rv := reflect.Indirect(reflect.ValueOf(row))
+	if !rv.IsValid() || rv.Type() != s.rowType {
+		panic(fmt.Sprintf("liststore: row must be a %s, got %T", s.rowType.Name(), row))
+	}
+	return rv
+}
+
+// value converts a field value to the column GValue.
+func (s *Store) value(col int, v reflect.Value) *externglib.Value {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return externglib.InitValue(s.types[col]) // Unset: NULL object.
+	}
+	if obj, ok := v.Interface().(externglib.Objector); ok {
+		// Typed as the column: NewValue types all objects as GObject, which
+		// the store can't convert to a subclass, like GdkPixbuf.
+		value := externglib.InitValue(s.types[col])
+		value.SetInstance(externglib.InternObject(obj).Native())
+		return value
+	}
+	return externglib.NewValue(v.Interface())
+}
@@ -0,0 +1,172 @@
+package liststore
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diamondburned/gotk4/pkg/gdkpixbuf/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+)
+
+// testRow has a field of each supported kind.
+type testRow struct {
+	Bool    bool
+	String  string
+	Int     int
+	Int8    int8
+	Int32   int32
+	Int64   int64
+	Uint    uint
+	Uint8   uint8
+	Uint32  uint32
+	Uint64  uint64
+	Float32 float32
+	Float64 float64
+	Icon    *gdkpixbuf.Pixbuf `gtype:"GdkPixbuf"`
+	Skipped string            `gtype:"-"`
+	private int
+}
+
+func init() {
+	newTestPixbuf() // Registers the GdkPixbuf type, found by name by the gtype tags.
+}
+
+func newTestPixbuf() *gdkpixbuf.Pixbuf {
+	return gdkpixbuf.NewPixbuf(gdkpixbuf.ColorspaceRGB, true, 8, 4, 4)
+}
+
+func TestColumnTypes(t *testing.T) {
+	store := New(testRow{})
+
+	want := []struct {
+		Field string
+		Type  externglib.Type
+	}{
+		{"Bool", externglib.TypeBoolean},
+		{"String", externglib.TypeString},
+		{"Int", externglib.TypeInt},
+		{"Int8", externglib.TypeChar},
+		{"Int32", externglib.TypeLong},
+		{"Int64", externglib.TypeInt64},
+		{"Uint", externglib.TypeUint},
+		{"Uint8", externglib.TypeUchar},
+		{"Uint32", externglib.TypeUlong},
+		{"Uint64", externglib.TypeUint64},
+		{"Float32", externglib.TypeFloat},
+		{"Float64", externglib.TypeDouble},
+		{"Icon", externglib.TypeFromName("GdkPixbuf")},
+	}
+	if n := store.NColumns(); n != len(want) {
+		t.Fatalf("%d columns, want %d: skipped and unexported fields aren't columns", n, len(want))
+	}
+	for col, w := range want {
+		if got := store.Column(w.Field); got != col {
+			t.Errorf("field %s: column %d, want %d", w.Field, got, col)
+		}
+		if got := store.ColumnType(col); got != w.Type {
+			t.Errorf("field %s: GType %s, want %s", w.Field, got, w.Type)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	store := New(testRow{})
+	pixbuf := newTestPixbuf()
+	rows := []testRow{
+		{true, "first", -1, -8, -32, -64, 1, 8, 32, 64, 1.5, 2.5, pixbuf, "", 0},
+		{false, "second", 1 << 20, 127, 1 << 30, 1 << 40, 7, 255, 1 << 31, 1 << 50, -0.25, -1e100, nil, "", 0},
+	}
+	for _, row := range rows {
+		store.Append(row)
+	}
+
+	got := store.Rows().([]testRow)
+	if len(got) != len(rows) {
+		t.Fatalf("%d rows, want %d", len(got), len(rows))
+	}
+	for i := range rows {
+		checkRow(t, got[i], rows[i])
+	}
+
+	// Skipped and unexported fields are left unset.
+	rows[0].Skipped, rows[0].private = "skipped", 1
+	store.Set(firstIter(t, store), &rows[0]) // Pointers work too.
+	var row testRow
+	store.Get(firstIter(t, store), &row)
+	if row.Skipped != "" || row.private != 0 {
+		t.Errorf("got skipped fields %q, %d, want them unset", row.Skipped, row.private)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	store := New(testRow{})
+	iter := store.Append(testRow{String: "before", Float64: 1, Icon: newTestPixbuf()})
+
+	want := testRow{Bool: true, String: "after", Int32: 42, Float64: 2} // Icon cleared.
+	store.Set(&iter, want)
+	var row testRow
+	store.Get(&iter, &row)
+	checkRow(t, row, want)
+
+	// SetField converts the value to the field type, like an untyped constant.
+	store.SetField(&iter, "Float64", 3)
+	store.SetField(&iter, "Int32", 7)
+	pixbuf := newTestPixbuf()
+	store.SetField(&iter, "Icon", pixbuf)
+	want.Float64, want.Int32, want.Icon = 3, 7, pixbuf
+	store.Get(&iter, &row)
+	checkRow(t, row, want)
+}
+
+func TestPanics(t *testing.T) {
+	for name, call := range map[string]func(){
+		"not a struct":   func() { New(1) },
+		"untagged slice": func() { New(struct{ List []int }{}) },
+		"unknown GType": func() {
+			New(struct {
+				X int `gtype:"NoSuchType"`
+			}{})
+		},
+		"unknown field": func() { New(testRow{}).Column("None") },
+		"row type":      func() { New(testRow{}).Append(struct{ Bool bool }{}) },
+	} {
+		call := call // We're in a loop, so we need to make a static copy for the callback.
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			call()
+		})
+	}
+}
+
+func firstIter(t *testing.T, store *Store) *gtk.TreeIter {
+	iter, ok := store.IterFirst()
+	if !ok {
+		t.Fatal("empty store")
+	}
+	return &iter
+}
+
+// checkRow compares the rows, and their pixbufs by instance.
+func checkRow(t *testing.T, got, want testRow) {
+	t.Helper()
+	if native(got.Icon) != native(want.Icon) {
+		t.Errorf("got pixbuf %#x, want %#x", native(got.Icon), native(want.Icon))
+	}
+	got.Icon, want.Icon = nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+}
+
+func native(pixbuf *gdkpixbuf.Pixbuf) uintptr {
+	if pixbuf == nil {
+		return 0
+	}
+	return externglib.InternObject(pixbuf).Native()
+}
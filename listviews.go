@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"runtime/cgo"
+	"strings"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+var listLists = Group{
+	{"ListView", newListView, "list rows recycling selection filter"},
+	{"GridView", newGridView, "grid icons recycling multiple selection"},
+	{"ColumnView", newColumnView, "table columns sort filter"},
+}
+
+//
+//---------------------------------------------------------------[ LIST ROWS ]--
+
+// listRowsCount is the number of rows of the list widgets, enough to show the
+// recycling of row widgets.
+const listRowsCount = 100000
+
+// listRow defines the data of a list row.
+type listRow struct {
+	Name  string
+	Size  int
+	Color string
+}
+
+// ListRows is a list model shared by the list widgets. Items are GObjects,
+// linked to the Go data by their pointer.
+type ListRows struct {
+	Store *gio.ListStore
+	rows  []listRow
+	index map[uintptr]int // Row index by item pointer.
+}
+
+var listRows *ListRows // Created on first use.
+
+// galleryRows returns the shared list rows, created on first use.
+func galleryRows() *ListRows {
+	if listRows != nil {
+		return listRows
+	}
+	colors := []string{"red", "orange", "yellow", "green", "blue", "purple"}
+	l := &ListRows{
+		Store: gio.NewListStore(externglib.TypeObject),
+		index: make(map[uintptr]int, listRowsCount),
+	}
+	items := make([]*externglib.Object, listRowsCount)
+	for i := range items {
+		row := listRow{
+			Name:  fmt.Sprintf("Item %06d", i),
+			Size:  (i * 7919) % 10007, // Pseudo random, to show the sorting.
+			Color: colors[(i*31)%len(colors)],
+		}
+		items[i] = NewStringObject(row.Name)
+		l.rows = append(l.rows, row)
+		l.index[items[i].Native()] = i
+	}
+	l.Store.Splice(0, 0, items) // One items-changed signal for all rows.
+	listRows = l
+	return l
+}
+
+// Row returns the data of a list item.
+func (l *ListRows) Row(item *externglib.Object) *listRow {
+	return l.row(item.Native())
+}
+
+// row returns the data of a list item by its pointer. It panics on items not
+// created by the ListRows.
+func (l *ListRows) row(ptr uintptr) *listRow {
+	i, ok := l.index[ptr]
+	if !ok {
+		panic(fmt.Sprintf("ListRows: unknown item %#x", ptr))
+	}
+	return &l.rows[i]
+}
+
+// Sorter creates a sorter comparing rows.
+func (l *ListRows) Sorter(less func(a, b *listRow) bool) *gtk.Sorter {
+	sorter := gtk.NewCustomSorter(func(a, b cgo.Handle) int {
+		ra, rb := l.row(CompareDataPointer(a)), l.row(CompareDataPointer(b))
+		switch {
+		case less(ra, rb):
+			return -1
+		case less(rb, ra):
+			return 1
+		}
+		return 0
+	})
+	return &sorter.Sorter
+}
+
+// newListFilter creates a model filtering the rows names with the search entry
+// text, and a label counting the rows shown.
+func newListFilter(l *ListRows, model gio.ListModeller) (*gtk.FilterListModel, gtk.Widgetter) {
+	entry := gtk.NewSearchEntry()
+	entry.SetHExpand(true)
+	filter := gtk.NewCustomFilter(func(item *externglib.Object) bool {
+		return strings.Contains(strings.ToLower(l.Row(item).Name), strings.ToLower(entry.Text()))
+	})
+	filtered := gtk.NewFilterListModel(model, &filter.Filter)
+	count := gtk.NewLabel("")
+	updateCount := func() { count.SetText(fmt.Sprintf("%d / %d", filtered.NItems(), listRowsCount)) }
+	filtered.Connect("items-changed", updateCount)
+	entry.Connect("search-changed", func() { filter.Changed(gtk.FilterChangeDifferent) })
+	updateCount()
+	return filtered, gtknew.HBox(boxMargin, entry, count)
+}
+
+// newLabelFactory creates a factory of labels, with markup text from the row.
+// Labels are created for the visible rows only, then reused (bound) for other
+// rows when scrolling.
+func newLabelFactory(l *ListRows, markup func(*listRow) string) *gtk.ListItemFactory {
+	factory := gtk.NewSignalListItemFactory()
+	factory.Connect("setup", func(_ *gtk.SignalListItemFactory, item *gtk.ListItem) {
+		label := gtk.NewLabel("")
+		label.SetXAlign(0)
+		item.SetChild(label)
+	})
+	factory.Connect("bind", func(_ *gtk.SignalListItemFactory, item *gtk.ListItem) {
+		item.Child().(*gtk.Label).SetMarkup(markup(l.Row(item.Item())))
+	})
+	return &factory.ListItemFactory
+}
+
+// newListScroll wraps a list widget in a scrolled window of a fixed height.
+func newListScroll(w gtk.Widgetter) *gtk.ScrolledWindow {
+	scroll := gtknew.ScrolledWindow(w)
+	scroll.SetSizeRequest(-1, 300)
+	scroll.SetVExpand(true)
+	return scroll
+}
+
+//
+//--------------------------------------------------------------[ LIST VIEWS ]--
+
+// newListSelection creates a selection model, named by the selection mode.
+func newListSelection(mode string, model gio.ListModeller) gtk.SelectionModeller {
+	var sel gtk.SelectionModeller
+	switch mode {
+	case "Multiple":
+		sel = gtk.NewMultiSelection(model)
+	case "None":
+		sel = gtk.NewNoSelection(model)
+	default:
+		sel = gtk.NewSingleSelection(model)
+	}
+	externglib.InternObject(sel).Connect("selection-changed", func(pos, n uint) { // The first argument (model) is skipped.
+		events.Log("ListView", "selection-changed", mode, pos, n)
+	})
+	return sel
+}
+
+func newListView() gtk.Widgetter {
+	l := galleryRows()
+	filtered, search := newListFilter(l, l.Store)
+	modes := []string{"Single", "Multiple", "None"}
+
+	factory := newLabelFactory(l, func(row *listRow) string {
+		return fmt.Sprintf("%s  <span foreground=\"%s\">●</span>  <small>%d</small>", row.Name, row.Color, row.Size)
+	})
+	w := gtk.NewListView(newListSelection(modes[0], filtered), factory)
+	w.Connect("activate", func(_ *gtk.ListView, pos uint) { events.Log("ListView", "activate", pos) })
+
+	mode := gtk.NewDropDownFromStrings(modes)
+	mode.Connect("notify::selected", func() { w.SetModel(newListSelection(modes[mode.Selected()], filtered)) })
+
+	return gtknew.VBox(boxMargin, gtknew.HBox(boxMargin, gtk.NewLabel("Selection"), mode), search, newListScroll(w))
+}
+
+func newGridView() gtk.Widgetter {
+	l := galleryRows()
+	filtered, search := newListFilter(l, l.Store)
+
+	sel := gtk.NewMultiSelection(filtered)
+	sel.Connect("selection-changed", func() { events.Log("GridView", "selection-changed", sel.Selection().Size()) })
+
+	factory := newLabelFactory(l, func(row *listRow) string {
+		return fmt.Sprintf("<span size=\"xx-large\" foreground=\"%s\">■</span>\n%s", row.Color, row.Name)
+	})
+	w := gtk.NewGridView(sel, factory)
+	w.SetMaxColumns(6)
+	w.Connect("activate", func(_ *gtk.GridView, pos uint) { events.Log("GridView", "activate", pos) })
+
+	return gtknew.VBox(boxMargin, search, newListScroll(w))
+}
+
+func newColumnView() gtk.Widgetter {
+	l := galleryRows()
+	sorted := gtk.NewSortListModel(l.Store, nil) // The sorter is set by the view columns.
+	filtered, search := newListFilter(l, sorted)
+
+	sel := gtk.NewSingleSelection(filtered)
+	sel.Connect("selection-changed", func() {
+		if item := sel.SelectedItem(); item != nil {
+			events.Log("ColumnView", "selection-changed", l.Row(item).Name)
+		}
+	})
+	w := gtk.NewColumnView(sel)
+	w.SetShowRowSeparators(true)
+
+	for _, col := range []struct {
+		title  string
+		markup func(*listRow) string
+		less   func(a, b *listRow) bool
+	}{
+		{"Name", func(r *listRow) string { return r.Name }, func(a, b *listRow) bool { return a.Name < b.Name }},
+		{"Size", func(r *listRow) string { return fmt.Sprint(r.Size) }, func(a, b *listRow) bool { return a.Size < b.Size }},
+		{"Color", func(r *listRow) string { return "<span foreground=\"" + r.Color + "\">" + r.Color + "</span>" }, func(a, b *listRow) bool { return a.Color < b.Color }},
+	} {
+		column := gtk.NewColumnViewColumn(col.title, newLabelFactory(l, col.markup))
+		column.SetSorter(l.Sorter(col.less))
+		column.SetExpand(true)
+		w.AppendColumn(column)
+	}
+	sorted.SetSorter(w.Sorter())
+	w.Sorter().Connect("changed", callLog("ColumnView", "sorter-changed"))
+
+	return gtknew.VBox(boxMargin, search, newListScroll(w))
+}
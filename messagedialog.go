@@ -0,0 +1,44 @@
+package main
+
+// #cgo pkg-config: gtk4
+// #include <stdlib.h>
+// #include <gtk/gtk.h>
+//
+// static GtkWidget *gallery_message_dialog_new(guintptr parent, GtkMessageType typ, GtkButtonsType buttons, const gchar *text) {
+// 	return gtk_message_dialog_new((GtkWindow *)parent, GTK_DIALOG_MODAL | GTK_DIALOG_DESTROY_WITH_PARENT, typ, buttons, "%s", text);
+// }
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+)
+
+//
+//----------------------------------------------------------[ MESSAGE DIALOG ]--
+
+// NewMessageDialog creates a modal message dialog, transient for parent.
+// The constructor is variadic in C, so it isn't generated by gotk4.
+func NewMessageDialog(parent *gtk.Window, typ gtk.MessageType, buttons gtk.ButtonsType, text string) *gtk.MessageDialog {
+	var cparent C.guintptr
+	if parent != nil {
+		cparent = C.guintptr(parent.Native())
+	}
+	ctext := cString(text)
+	defer C.free(unsafe.Pointer(ctext))
+
+	ptr := C.gallery_message_dialog_new(cparent, C.GtkMessageType(typ), C.GtkButtonsType(buttons), ctext)
+	return externglib.Take(unsafe.Pointer(ptr)).Cast().(*gtk.MessageDialog)
+}
+
+// OnResponse calls back with the typed response of the dialog, then destroys
+// it. The callback runs in the GTK main loop.
+func OnResponse(dialog *gtk.Dialog, call func(gtk.ResponseType)) {
+	dialog.Connect("response", func(_ *gtk.Dialog, resp int) {
+		call(gtk.ResponseType(resp))
+		dialog.Destroy()
+	})
+}
@@ -0,0 +1,132 @@
+// Package pixbufio decodes images in streaming with a gdkpixbuf.PixbufLoader
+// usable as an io.WriteCloser.
+//
+// Images can be piped straight from an http.Response.Body or a file:
+//
+//	dec := pixbufio.NewDecoder(pixbufio.SizeFit, 48, 48)
+//	_, e := io.Copy(dec, resp.Body)
+//	if e == nil {
+//	  e = dec.Close()
+//	}
+//	pixbuf := dec.Pixbuf()
+package pixbufio
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gdkpixbuf/v2"
+)
+
+// ErrClosed is returned when writing to a closed Decoder.
+var ErrClosed = errors.New("pixbufio: write on closed decoder")
+
+// SizePolicy defines how the image is scaled while decoding.
+type SizePolicy int
+
+// Size policies.
+const (
+	SizeOriginal SizePolicy = iota // Keep the image size.
+	SizeExact                      // Scale to the target size, ignoring the aspect ratio.
+	SizeFit                        // Scale to fit in the target size, keeping the aspect ratio.
+)
+
+// Decoder decodes an image written to it, as an io.WriteCloser.
+type Decoder struct {
+	Policy SizePolicy
+	Width  int // Target width, for SizeExact and SizeFit.
+	Height int // Target height, for SizeExact and SizeFit.
+
+	// OnSize is called with the original image size, when known.
+	OnSize func(width, height int)
+
+	// OnFormat is called with the image format name and MIME types, when known.
+	OnFormat func(name string, mimeTypes []string)
+
+	loader *gdkpixbuf.PixbufLoader
+	closed bool
+}
+
+// NewDecoder creates a decoder scaling images to the target size with the
+// policy.
+func NewDecoder(policy SizePolicy, width, height int) *Decoder {
+	dec := &Decoder{
+		Policy: policy,
+		Width:  width,
+		Height: height,
+		loader: gdkpixbuf.NewPixbufLoader(),
+	}
+	dec.loader.Connect("size-prepared", dec.sizePrepared)
+	return dec
+}
+
+// Write sends image data to the decoder.
+func (dec *Decoder) Write(data []byte) (int, error) {
+	if dec.closed {
+		return 0, ErrClosed
+	}
+	if e := dec.loader.Write(data); e != nil {
+		return 0, e
+	}
+	return len(data), nil
+}
+
+// Close finishes the decoding. It must be called before using the image.
+func (dec *Decoder) Close() error {
+	if dec.closed {
+		return nil
+	}
+	dec.closed = true
+	return dec.loader.Close()
+}
+
+// Pixbuf returns the decoded image, or nil if no image was decoded.
+func (dec *Decoder) Pixbuf() *gdkpixbuf.Pixbuf { return dec.loader.Pixbuf() }
+
+// Texture returns the decoded image as a texture, or nil if no image was decoded.
+func (dec *Decoder) Texture() *gdk.Texture {
+	pix := dec.Pixbuf()
+	if pix == nil {
+		return nil
+	}
+	return gdk.NewTextureForPixbuf(pix)
+}
+
+// sizePrepared is called by the loader when the image size is known, to
+// choose the decoded size.
+func (dec *Decoder) sizePrepared(loader *gdkpixbuf.PixbufLoader, width, height int) {
+	if dec.OnFormat != nil {
+		if format := loader.Format(); format != nil {
+			dec.OnFormat(format.Name(), format.MIMETypes())
+		}
+	}
+	if dec.OnSize != nil {
+		dec.OnSize(width, height)
+	}
+
+	if dec.Width <= 0 || dec.Height <= 0 || width <= 0 || height <= 0 {
+		return
+	}
+	switch dec.Policy {
+	case SizeExact:
+		loader.SetSize(dec.Width, dec.Height)
+	case SizeFit:
+		ratio := math.Min(float64(dec.Width)/float64(width), float64(dec.Height)/float64(height))
+		loader.SetSize(int(math.Max(1, math.Round(float64(width)*ratio))), int(math.Max(1, math.Round(float64(height)*ratio))))
+	}
+}
+
+// Decode reads and decodes an image with the size policy.
+func Decode(r io.Reader, policy SizePolicy, width, height int) (*gdkpixbuf.Pixbuf, error) {
+	dec := NewDecoder(policy, width, height)
+	_, e := io.Copy(dec, r)
+	if ec := dec.Close(); e == nil {
+		e = ec
+	}
+	if e != nil {
+		return nil, e
+	}
+	return dec.Pixbuf(), nil
+}
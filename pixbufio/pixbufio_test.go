@@ -0,0 +1,138 @@
+package pixbufio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/gtkool4/gallery/assets"
+)
+
+// testImage is a 200x200 PNG, from the gallery assets.
+const testImage = "gotk4.png"
+
+func readTestImage(t *testing.T) []byte {
+	byts, e := fs.ReadFile(assets.Bundled, testImage)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return byts
+}
+
+func TestDecoderSizes(t *testing.T) {
+	byts := readTestImage(t)
+	for _, test := range []struct {
+		Name          string
+		Policy        SizePolicy
+		Width, Height int // Target.
+		WantW, WantH  int
+	}{
+		{"original", SizeOriginal, 50, 100, 200, 200},
+		{"exact", SizeExact, 50, 100, 50, 100},
+		{"fit", SizeFit, 50, 100, 50, 50}, // Aspect ratio kept.
+		{"fit wide", SizeFit, 300, 100, 100, 100},
+		{"no target", SizeFit, 0, 0, 200, 200},
+	} {
+		test := test // We're in a loop, so we need to make a static copy for the callback.
+		t.Run(test.Name, func(t *testing.T) {
+			dec := NewDecoder(test.Policy, test.Width, test.Height)
+			if _, e := io.Copy(dec, bytes.NewReader(byts)); e != nil {
+				t.Fatal(e)
+			}
+			if e := dec.Close(); e != nil {
+				t.Fatal(e)
+			}
+			pix := dec.Pixbuf()
+			if pix == nil {
+				t.Fatal("no pixbuf")
+			}
+			if pix.Width() != test.WantW || pix.Height() != test.WantH {
+				t.Errorf("got %dx%d, want %dx%d", pix.Width(), pix.Height(), test.WantW, test.WantH)
+			}
+			if dec.Texture() == nil {
+				t.Error("no texture")
+			}
+		})
+	}
+}
+
+func TestDecoderCallbacks(t *testing.T) {
+	var sizes [][2]int
+	var formats []string
+	var mimeTypes []string
+	dec := NewDecoder(SizeFit, 48, 48)
+	dec.OnSize = func(width, height int) { sizes = append(sizes, [2]int{width, height}) }
+	dec.OnFormat = func(name string, types []string) {
+		formats = append(formats, name)
+		mimeTypes = types
+	}
+
+	if _, e := io.Copy(dec, bytes.NewReader(readTestImage(t))); e != nil {
+		t.Fatal(e)
+	}
+	if e := dec.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	if len(sizes) != 1 || sizes[0] != [2]int{200, 200} {
+		t.Errorf("OnSize calls %v, want once with the original size 200x200", sizes)
+	}
+	if len(formats) != 1 || formats[0] != "png" {
+		t.Errorf("OnFormat calls %v, want once with png", formats)
+	}
+	found := false
+	for _, typ := range mimeTypes {
+		found = found || typ == "image/png"
+	}
+	if !found {
+		t.Errorf("MIME types %v, want image/png", mimeTypes)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	byts := readTestImage(t)
+
+	t.Run("write after close", func(t *testing.T) {
+		dec := NewDecoder(SizeOriginal, 0, 0)
+		if _, e := dec.Write(byts); e != nil {
+			t.Fatal(e)
+		}
+		if e := dec.Close(); e != nil {
+			t.Fatal(e)
+		}
+		if _, e := dec.Write(byts); !errors.Is(e, ErrClosed) {
+			t.Errorf("got %v, want ErrClosed", e)
+		}
+		if e := dec.Close(); e != nil {
+			t.Errorf("second close: %v", e)
+		}
+	})
+
+	for name, data := range map[string][]byte{
+		"truncated": byts[:len(byts)/2],
+		"garbage":   bytes.Repeat([]byte("not an image "), 200),
+	} {
+		data := data // We're in a loop, so we need to make a static copy for the callback.
+		t.Run(name, func(t *testing.T) {
+			dec := NewDecoder(SizeOriginal, 0, 0)
+			_, errWrite := io.Copy(dec, bytes.NewReader(data))
+			errClose := dec.Close()
+			if errWrite == nil && errClose == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
+
+func TestDecoderEmpty(t *testing.T) {
+	dec := NewDecoder(SizeFit, 48, 48)
+	dec.Close()
+	if dec.Pixbuf() != nil {
+		t.Error("got a pixbuf, without data")
+	}
+	if dec.Texture() != nil {
+		t.Error("got a texture, without data")
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/diamondburned/gotk4/pkg/cairo"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+)
+
+//
+//------------------------------------------------------------------[ REPORT ]--
+
+// Report page layout, in points.
+const (
+	reportMargin     = 36
+	reportFontSize   = 10
+	reportLineHeight = 14
+)
+
+// printing keeps the page setup and print settings between operations.
+var printing = &Printing{}
+
+// Printing holds the page setup chosen in the PageSetupDialog entry and the
+// settings of the last print operation.
+type Printing struct {
+	setup    *gtk.PageSetup
+	settings *gtk.PrintSettings
+}
+
+// PageSetup returns the page setup, created with defaults when unset.
+func (p *Printing) PageSetup() *gtk.PageSetup {
+	if p.setup == nil {
+		p.setup = gtk.NewPageSetup()
+	}
+	return p.setup
+}
+
+// Settings returns the print settings, created with defaults when unset.
+func (p *Printing) Settings() *gtk.PrintSettings {
+	if p.settings == nil {
+		p.settings = gtk.NewPrintSettings()
+	}
+	return p.settings
+}
+
+// reportLine is a text line of the report.
+type reportLine struct {
+	Text   string
+	Bold   bool
+	Indent int
+}
+
+// galleryReport lists the gallery widgets and the inspected widget properties.
+func galleryReport() []reportLine {
+	lines := []reportLine{{Text: gapp.Title, Bold: true}, {}}
+	for _, group := range groups {
+		lines = append(lines, reportLine{Text: group.Title, Bold: true})
+		for _, item := range group.List {
+			lines = append(lines, reportLine{Text: fmt.Sprintf("%-22s %s", item.Name, item.Tags), Indent: 1})
+		}
+		lines = append(lines, reportLine{})
+	}
+
+	var name string
+	var obj *externglib.Object
+	if inspector != nil { // Nil until a window is built, like in --smoke or --screenshots modes.
+		name, obj = inspector.Target()
+	}
+	if obj == nil {
+		return append(lines, reportLine{Text: "Click a gallery widget to add its properties.", Indent: 1})
+	}
+	lines = append(lines, reportLine{Text: name + " properties (" + obj.TypeFromInstance().Name() + ")", Bold: true})
+	for _, spec := range ListProperties(obj) {
+		typ := externglib.FundamentalType(spec.Type)
+		if !spec.Readable || !(typ == externglib.TypeBoolean || typ == externglib.TypeString || typ == externglib.TypeEnum || isNumberType(typ)) {
+			continue
+		}
+		value := fmt.Sprint(obj.ObjectProperty(spec.Name))
+		lines = append(lines, reportLine{Text: fmt.Sprintf("%-28s %s", spec.Name, value), Indent: 1})
+	}
+	return lines
+}
+
+// newReportOperation creates a print operation for the gallery report: text
+// pages followed by a page with a cairo drawing.
+func newReportOperation() *gtk.PrintOperation {
+	lines := galleryReport()
+	perPage := 1
+
+	op := gtk.NewPrintOperation()
+	op.SetJobName("gallery-report")
+	op.SetUnit(gtk.UnitPoints)
+	op.SetDefaultPageSetup(printing.PageSetup())
+	op.SetPrintSettings(printing.Settings())
+
+	op.Connect("begin-print", func(op *gtk.PrintOperation, ctx *gtk.PrintContext) {
+		perPage = int((ctx.Height() - 2*reportMargin) / reportLineHeight)
+		if perPage < 1 {
+			perPage = 1
+		}
+		textPages := (len(lines) + perPage - 1) / perPage
+		op.SetNPages(textPages + 1)
+		events.Log("PrintDialog", "begin-print", textPages+1)
+	})
+
+	op.Connect("draw-page", func(op *gtk.PrintOperation, ctx *gtk.PrintContext, page int) {
+		cr := ctx.CairoContext()
+		start := page * perPage
+		if start >= len(lines) { // Last page.
+			drawReportSample(cr, ctx.Width(), ctx.Height())
+			return
+		}
+		end := start + perPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+		cr.SetSourceRGB(0, 0, 0)
+		cr.SetFontSize(reportFontSize)
+		for i, line := range lines[start:end] {
+			weight := cairo.FONT_WEIGHT_NORMAL
+			if line.Bold {
+				weight = cairo.FONT_WEIGHT_BOLD
+			}
+			cr.SelectFontFace("monospace", cairo.FONT_SLANT_NORMAL, weight)
+			cr.MoveTo(reportMargin+float64(line.Indent)*4*reportFontSize, reportMargin+float64(i+1)*reportLineHeight)
+			cr.ShowText(line.Text)
+		}
+	})
+
+	op.Connect("done", func(op *gtk.PrintOperation, result gtk.PrintOperationResult) {
+		events.Log("PrintDialog", "done", result)
+	})
+	return op
+}
+
+// drawReportSample draws the DrawingArea sample, centered in the page.
+func drawReportSample(cr *cairo.Context, width, height float64) {
+	cr.SetSourceRGB(0, 0, 0)
+	cr.SelectFontFace("sans-serif", cairo.FONT_SLANT_NORMAL, cairo.FONT_WEIGHT_BOLD)
+	cr.SetFontSize(2 * reportFontSize)
+	cr.MoveTo(reportMargin, reportMargin+2*reportFontSize)
+	cr.ShowText("DrawingArea")
+
+	size := math.Min(width, height) - 2*reportMargin
+	cr.Translate((width-size)/2, (height-size/2)/2)
+	drawCircles(cr, size, size/2)
+}
+
+// ExportReport exports the gallery report to a PDF file. It doesn't need a
+// printer, nor any user interaction.
+func ExportReport(path string) error {
+	op := newReportOperation()
+	op.SetExportFilename(path)
+	_, e := op.Run(gtk.PrintOperationActionExport, &gapp.Win.Window)
+	return e
+}
+
+// PrintReport shows the print dialog for the gallery report. Settings applied
+// are kept for the next operations.
+func PrintReport() error {
+	op := newReportOperation()
+	result, e := op.Run(gtk.PrintOperationActionPrintDialog, &gapp.Win.Window)
+	if result == gtk.PrintOperationResultApply {
+		printing.settings = op.PrintSettings()
+	}
+	if e != nil {
+		return fmt.Errorf("print: %w", e)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Package registry collects the gallery entries: widget makers sorted by group,
+// with their metadata.
+//
+// Packages register their entries in an init function, and are added to the
+// gallery binary with a blank import:
+//
+//	package widgets
+//
+//	func init() {
+//		registry.Register("Custom", "Knob", NewKnobExample,
+//			registry.Tags("dial rotary value"),
+//			registry.Description("A rotary value selector."),
+//			registry.MinGTK(4, 2, 0),
+//		)
+//	}
+//
+//	// In the gallery main package:
+//	import _ "example.com/widgets"
+//
+// Registration errors are programming errors: they panic.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// Entry is a gallery entry: a widget example with its metadata.
+type Entry struct {
+	Group       string
+	Name        string
+	Make        func() gtk.Widgetter
+	Tags        string  // Free text keywords, used by the search.
+	Description string  // Short help text.
+	MinGTK      Version // Oldest GTK version able to run the example. Zero for any.
+	Source      string  // Code displayed. The maker source is found when empty (gallery only).
+}
+
+// Supported returns true if the entry can run with the running GTK version.
+func (e Entry) Supported(running Version) bool { return e.MinGTK.IsZero() || !running.Less(e.MinGTK) }
+
+// Version is a GTK version, major, minor and micro.
+type Version struct {
+	Major, Minor, Micro int
+}
+
+// IsZero returns true when the version is unset.
+func (v Version) IsZero() bool { return v == Version{} }
+
+// Less returns true if v is older than other.
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Micro < other.Micro
+}
+
+func (v Version) String() string { return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Micro) }
+
+// Option sets entry metadata on Register.
+type Option func(*Entry)
+
+// Tags sets the free text keywords of the entry, used by the search.
+func Tags(tags string) Option { return func(e *Entry) { e.Tags = tags } }
+
+// Description sets the short help text of the entry.
+func Description(text string) Option { return func(e *Entry) { e.Description = text } }
+
+// MinGTK sets the oldest GTK version able to run the entry example.
+func MinGTK(major, minor, micro int) Option {
+	return func(e *Entry) { e.MinGTK = Version{major, minor, micro} }
+}
+
+// Source sets the code displayed for the entry.
+func Source(code string) Option { return func(e *Entry) { e.Source = code } }
+
+// Group is a list of entries, in registration order.
+type Group struct {
+	Title string
+	List  []Entry
+}
+
+// groups lists the registered groups, by first registration.
+var groups []*Group
+
+// Register adds an entry to the group, created when needed. Names ignore case.
+// Entries must be registered before the gallery starts, like in init functions.
+func Register(group, name string, maker func() gtk.Widgetter, opts ...Option) {
+	if group == "" || name == "" || maker == nil {
+		panic(fmt.Sprintf("registry: entry %q/%q needs a group, a name and a maker", group, name))
+	}
+	if _, ok := Lookup(group, name); ok {
+		panic(fmt.Sprintf("registry: entry %s/%s already registered", group, name))
+	}
+
+	entry := Entry{Group: group, Name: name, Make: maker}
+	for _, opt := range opts {
+		opt(&entry)
+	}
+
+	for _, g := range groups {
+		if strings.EqualFold(g.Title, group) {
+			g.List = append(g.List, entry)
+			return
+		}
+	}
+	groups = append(groups, &Group{Title: group, List: []Entry{entry}})
+}
+
+// Groups returns the registered groups: those titled first, in this order,
+// then the others in their first registration order. Titles ignore case.
+func Groups(first ...string) []Group {
+	rank := func(g *Group) int {
+		for i, title := range first {
+			if strings.EqualFold(g.Title, title) {
+				return i - len(first) // Negative: before the others, at 0.
+			}
+		}
+		return 0
+	}
+	list := make([]Group, len(groups))
+	for i, g := range groups {
+		list[i] = Group{Title: g.Title, List: append([]Entry(nil), g.List...)}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return rank(&list[i]) < rank(&list[j]) })
+	return list
+}
+
+// Lookup returns the entry of the group, both names ignoring case.
+func Lookup(group, name string) (Entry, bool) {
+	for _, g := range groups {
+		if !strings.EqualFold(g.Title, group) {
+			continue
+		}
+		for _, entry := range g.List {
+			if strings.EqualFold(entry.Name, name) {
+				return entry, true
+			}
+		}
+	}
+	return Entry{}, false
+}
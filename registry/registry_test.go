@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func testMaker() gtk.Widgetter { return nil }
+
+// resetRegistry empties the registry for the test, and restores it after.
+func resetRegistry(t *testing.T) {
+	saved := groups
+	groups = nil
+	t.Cleanup(func() { groups = saved })
+}
+
+func TestRegisterPanics(t *testing.T) {
+	resetRegistry(t)
+	Register("Buttons", "Button", testMaker)
+
+	for name, call := range map[string]func(){
+		"empty group": func() { Register("", "Label", testMaker) },
+		"empty name":  func() { Register("Displays", "", testMaker) },
+		"nil maker":   func() { Register("Displays", "Label", nil) },
+		"duplicate":   func() { Register("buttons", "BUTTON", testMaker) }, // Names ignore case.
+	} {
+		call := call // We're in a loop, so we need to make a static copy for the callback.
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			call()
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	resetRegistry(t)
+	Register("Buttons", "Button", testMaker)
+	Register("Buttons", "Switch", testMaker)
+
+	entry, ok := Lookup("buttons", "switch")
+	if !ok || entry.Group != "Buttons" || entry.Name != "Switch" || entry.Make == nil {
+		t.Errorf("got %+v, %t, want Buttons/Switch", entry, ok)
+	}
+	for _, miss := range [][2]string{{"Buttons", "Label"}, {"Displays", "Button"}, {"", ""}} {
+		if entry, ok := Lookup(miss[0], miss[1]); ok {
+			t.Errorf("%s/%s: got %+v, want none", miss[0], miss[1], entry)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	resetRegistry(t)
+	// Imported packages are initialized first: their entries come first.
+	Register("Knobs", "Knob", testMaker)
+	Register("Custom", "Dial", testMaker)
+	Register("Displays", "Label", testMaker)
+	Register("Buttons", "Button", testMaker)
+	Register("custom", "Widget", testMaker) // Same group, the first title is kept.
+	Register("Dials", "Dial", testMaker)
+
+	titles := func(list []Group) (names []string) {
+		for _, g := range list {
+			names = append(names, g.Title)
+		}
+		return names
+	}
+	for _, test := range []struct {
+		First []string
+		Want  []string
+	}{
+		{nil, []string{"Knobs", "Custom", "Displays", "Buttons", "Dials"}},
+		{[]string{"Displays", "Buttons", "CUSTOM"}, []string{"Displays", "Buttons", "Custom", "Knobs", "Dials"}},
+		{[]string{"Missing", "Buttons"}, []string{"Buttons", "Knobs", "Custom", "Displays", "Dials"}},
+	} {
+		if got := titles(Groups(test.First...)); !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("Groups(%q): got %q, want %q", test.First, got, test.Want)
+		}
+	}
+
+	list := Groups()
+	if custom := list[1].List; len(custom) != 2 || custom[0].Name != "Dial" || custom[1].Name != "Widget" {
+		t.Errorf("got Custom entries %+v, want Dial then Widget", custom)
+	}
+
+	// The groups are copies.
+	list[1].List[0].Name = "Changed"
+	list[1].Title = "Changed"
+	if _, ok := Lookup("Custom", "Dial"); !ok {
+		t.Error("the registry was changed through Groups")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	resetRegistry(t)
+	Register("Custom", "Knob", testMaker,
+		Tags("dial rotary"),
+		Description("A rotary value selector."),
+		Source("func knob() {}"),
+		MinGTK(4, 2, 1),
+	)
+	Register("Custom", "Plain", testMaker)
+
+	entry, _ := Lookup("Custom", "Knob")
+	entry.Make = nil // Funcs can't be compared.
+	want := Entry{
+		Group:       "Custom",
+		Name:        "Knob",
+		Tags:        "dial rotary",
+		Description: "A rotary value selector.",
+		Source:      "func knob() {}",
+		MinGTK:      Version{4, 2, 1},
+	}
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("got %+v, want %+v", entry, want)
+	}
+
+	plain, _ := Lookup("Custom", "Plain")
+	plain.Make = nil
+	if !reflect.DeepEqual(plain, Entry{Group: "Custom", Name: "Plain"}) {
+		t.Errorf("got %+v, want no metadata", plain)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	for _, test := range []struct {
+		Older, Newer Version
+	}{
+		{Version{4, 2, 0}, Version{4, 2, 1}},   // Micro.
+		{Version{4, 2, 9}, Version{4, 10, 0}},  // Minor, not by text.
+		{Version{4, 2, 10}, Version{4, 3, 0}},  // Minor before micro.
+		{Version{3, 24, 30}, Version{4, 0, 0}}, // Major.
+		{Version{}, Version{0, 0, 1}},
+	} {
+		if !test.Older.Less(test.Newer) {
+			t.Errorf("%s not less than %s", test.Older, test.Newer)
+		}
+		if test.Newer.Less(test.Older) {
+			t.Errorf("%s less than %s", test.Newer, test.Older)
+		}
+		if test.Older.Less(test.Older) {
+			t.Errorf("%s less than itself", test.Older)
+		}
+	}
+
+	if s := (Version{4, 10, 2}).String(); s != "4.10.2" {
+		t.Errorf("got %q, want 4.10.2", s)
+	}
+	if !(Version{}).IsZero() || (Version{0, 0, 1}).IsZero() {
+		t.Error("IsZero: only the unset version is zero")
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, test := range []struct {
+		MinGTK, Running Version
+		Want            bool
+	}{
+		{Version{}, Version{4, 0, 0}, true}, // Any version.
+		{Version{4, 2, 1}, Version{4, 2, 1}, true},
+		{Version{4, 2, 1}, Version{4, 2, 0}, false}, // Micro.
+		{Version{4, 2, 1}, Version{4, 2, 2}, true},
+		{Version{4, 4, 0}, Version{4, 3, 9}, false}, // Minor.
+		{Version{4, 4, 0}, Version{4, 10, 0}, true},
+		{Version{4, 4, 0}, Version{3, 99, 99}, false}, // Major.
+		{Version{4, 4, 0}, Version{5, 0, 0}, true},
+	} {
+		entry := Entry{MinGTK: test.MinGTK}
+		if got := entry.Supported(test.Running); got != test.Want {
+			t.Errorf("MinGTK %s, running %s: got %t, want %t", test.MinGTK, test.Running, got, test.Want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+// #cgo pkg-config: gtk4
+// #include <stdlib.h>
+// #include <gtk/gtk.h>
+//
+// static gboolean gallery_widget_save_png(guintptr ptr, const gchar *filename) {
+// 	GtkWidget *widget = (GtkWidget *)ptr;
+// 	int width = gtk_widget_get_width(widget);
+// 	int height = gtk_widget_get_height(widget);
+//
+// 	GdkPaintable *paintable = gtk_widget_paintable_new(widget);
+// 	GtkSnapshot *snapshot = gtk_snapshot_new();
+// 	gdk_paintable_snapshot(paintable, snapshot, width, height);
+// 	GskRenderNode *node = gtk_snapshot_free_to_node(snapshot);
+// 	g_object_unref(paintable);
+// 	if (node == NULL) {
+// 		return FALSE;
+// 	}
+//
+// 	GskRenderer *renderer = gtk_native_get_renderer(gtk_widget_get_native(widget));
+// 	graphene_rect_t viewport = GRAPHENE_RECT_INIT(0, 0, width, height);
+// 	GdkTexture *texture = gsk_renderer_render_texture(renderer, node, &viewport);
+// 	gboolean ok = gdk_texture_save_to_png(texture, filename);
+// 	g_object_unref(texture);
+// 	gsk_render_node_unref(node);
+// 	return ok;
+// }
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//-------------------------------------------------------------[ SCREENSHOTS ]--
+
+// Screenshots render settings, the same on every machine so images can be
+// compared: light default theme, no animation (a frozen state).
+var screenshotSettings = map[string]interface{}{
+	"gtk-theme-name":                    "Adwaita",
+	"gtk-application-prefer-dark-theme": false,
+	"gtk-enable-animations":             false,
+	"gtk-cursor-blink":                  false,
+}
+
+// Screenshot timing: a shot is taken screenshotFrames ticks after the widget
+// got a size, to let it draw. It fails after smokeTicks.
+const screenshotFrames = 2
+
+// runScreenshots renders the gallery to PNG files in dir, with the window size
+// and screenshotSettings. Each entry is saved as group-entry.png, or each group
+// overview as gallery-group.png when overview is true (the README images).
+//
+// Like the smoke test, it runs headless on a virtual display:
+//
+//	xvfb-run go run . --screenshots docs/img
+func runScreenshots(dir string, overview bool) gtk.Widgetter {
+	settings := gtk.SettingsGetDefault()
+	for name, value := range screenshotSettings {
+		settings.SetObjectProperty(name, value)
+	}
+
+	type shot struct {
+		file  string
+		maker func() gtk.Widgetter
+	}
+	var list []shot
+	for _, group := range groups {
+		group := group // We're in a loop, so we need to make a static copy for the callback.
+		if overview {
+			list = append(list, shot{"gallery-" + group.Title, func() gtk.Widgetter { return newGroupWidgets(group.Title, group.List) }})
+			continue
+		}
+		for _, item := range group.List {
+			item := item // We're in a loop, so we need to make a static copy for the callback.
+			list = append(list, shot{group.Title + "-" + item.Name, func() gtk.Widgetter {
+				if !item.Supported(gtkVersion()) {
+					return gtknew.Frame(item.Name, newVersionCard(item))
+				}
+				return gtknew.Frame(item.Name, item.Make())
+			}})
+		}
+	}
+
+	box := gtknew.VBox(boxMargin)
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		fmt.Println("screenshots:", e)
+		externglib.IdleAdd(func() { gapp.Exit(1) })
+		return box
+	}
+
+	failed := 0
+	var take func(i int)
+	take = func(i int) {
+		if i == len(list) {
+			fmt.Printf("screenshots: %d saved in %s, %d failed\n", len(list)-failed, dir, failed)
+			if failed > 0 {
+				gapp.Exit(1)
+			} else {
+				gapp.Exit(0)
+			}
+			return
+		}
+
+		s := list[i]
+		path := filepath.Join(dir, screenshotName(s.file)+".png")
+		w, err := smokeMake(s.maker)
+		if err != nil {
+			fmt.Printf("FAIL  %s: %v\n", path, err)
+			failed++
+			externglib.IdleAdd(func() { take(i + 1) })
+			return
+		}
+		box.Append(w)
+
+		ticks, frames := 0, 0
+		externglib.TimeoutAdd(smokeTick, func() bool {
+			ticks++
+			if w.Realized() && w.AllocatedWidth() > 0 && w.AllocatedHeight() > 0 {
+				frames++
+			}
+			if frames < screenshotFrames && ticks < smokeTicks {
+				return true // Wait for the next frame.
+			}
+			err := SaveWidgetPNG(w, path)
+			if err != nil {
+				fmt.Printf("FAIL  %s: %v\n", path, err)
+				failed++
+			} else {
+				fmt.Printf("saved %s\n", path)
+			}
+			box.Remove(w)
+			take(i + 1)
+			return false
+		})
+	}
+
+	externglib.IdleAdd(func() { take(0) })
+	return box
+}
+
+// screenshotName returns a file name for the screenshot, in lower case, with
+// the characters other than letters, digits, _ and - replaced by -. Entry names
+// can have spaces, or a slash that would be a subdirectory.
+func screenshotName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(name))
+}
+
+// SaveWidgetPNG renders the widget, at its current size, to a PNG file.
+// The widget must be realized, in a window.
+func SaveWidgetPNG(w gtk.Widgetter, path string) error {
+	if !w.Realized() || w.AllocatedWidth() == 0 || w.AllocatedHeight() == 0 {
+		return errors.New("widget not shown")
+	}
+	cpath := cString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	if C.gallery_widget_save_png(C.guintptr(w.Native()), cpath) == 0 {
+		return errors.New("render failed")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/gallery/registry"
+)
+
+//
+//------------------------------------------------------------------[ SEARCH ]--
+
+// search is the gallery entries filter, shared by all pages.
+var search = &Search{}
+
+// Search filters gallery entries with a text query. Every word of the query
+// must be found in the entry name, group title or tags.
+type Search struct {
+	words   []string
+	filters []*func() // Pointers identify the filters to remove.
+}
+
+// SetQuery changes the search text and refreshes all filtered views.
+func (s *Search) SetQuery(text string) {
+	s.words = strings.Fields(strings.ToLower(text))
+	for _, call := range s.filters {
+		(*call)()
+	}
+}
+
+// Active returns true when a query is set.
+func (s *Search) Active() bool { return len(s.words) > 0 }
+
+// Match returns true if all query words are found in the given fields.
+func (s *Search) Match(fields ...string) bool {
+	text := strings.ToLower(strings.Join(fields, " "))
+	for _, word := range s.words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// OnChange registers a filter to call when the query changes, until the
+// returned remove func is called.
+// It's also called immediately, so views created later are filtered too.
+func (s *Search) OnChange(call func()) (remove func()) {
+	ref := &call
+	s.filters = append(s.filters, ref)
+	call()
+	return func() {
+		for i, f := range s.filters {
+			if f == ref {
+				s.filters = append(s.filters[:i], s.filters[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Filter registers a filter of the widget view, removed when the widget is
+// destroyed: pages rebuilt or closed don't leave their filters behind.
+func (s *Search) Filter(w gtk.Widgetter, call func()) {
+	remove := s.OnChange(call)
+	w.Connect("destroy", remove)
+}
+
+// MatchGroup returns true if any entry of the group matches.
+func (s *Search) MatchGroup(title string, list []registry.Entry) bool {
+	for _, item := range list {
+		if s.Match(title, item.Name, item.Tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFrame hides a frame packed in a container that doesn't match, or
+// highlights it when it does.
+func (s *Search) filterFrame(frame gtk.Widgetter, fields ...string) {
+	match := s.Match(fields...)
+	frame.Parent().SetVisible(match) // Hide the container child (FlowBoxChild) to prevent empty cells.
+	if match && s.Active() {
+		frame.AddCSSClass("search-match")
+	} else {
+		frame.RemoveCSSClass("search-match")
+	}
+}
+
+const searchCSS = `
+frame.search-match {
+	border-color: @theme_selected_bg_color;
+	box-shadow: 0 0 0 1px @theme_selected_bg_color;
+}
+frame.search-match > label {
+	font-weight: bold;
+}
+`
+
+// newGallerySearch creates the search entry for the gallery window.
+// Typing anywhere in the window starts a search.
+func newGallerySearch() gtk.Widgetter {
+	css := gtk.NewCSSProvider()
+	css.LoadFromData([]byte(searchCSS))
+	gtk.StyleContextAddProviderForDisplay(gdk.DisplayGetDefault(), css, gtk.STYLE_PROVIDER_PRIORITY_APPLICATION)
+
+	entry := gtk.NewSearchEntry()
+	entry.SetHExpand(true)
+	entry.SetObjectProperty("placeholder-text", "Search widgets by name, group or tag")
+	entry.SetKeyCaptureWidget(&gapp.Win.Widget) // Using .Widget to prevent the naming conflict.
+	entry.Connect("search-changed", func() { search.SetQuery(entry.Text()) })
+
+	bar := gtk.NewSearchBar()
+	bar.SetChild(entry)
+	bar.SetSearchMode(true)
+	return bar
+}
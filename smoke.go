@@ -0,0 +1,181 @@
+package main
+
+// #cgo pkg-config: gtk4
+// #include <gtk/gtk.h>
+//
+// static gint gallery_criticals;
+//
+// static GLogWriterOutput gallery_log_writer(GLogLevelFlags level, const GLogField *fields, gsize n, gpointer data) {
+// 	if (level & G_LOG_LEVEL_CRITICAL) {
+// 		g_atomic_int_inc(&gallery_criticals);
+// 	}
+// 	return g_log_writer_default(level, fields, n, data);
+// }
+//
+// static void gallery_count_criticals(void) { g_log_set_writer_func(gallery_log_writer, NULL, NULL); }
+//
+// static gint gallery_criticals_count(void) { return g_atomic_int_get(&gallery_criticals); }
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//--------------------------------------------------------------[ SMOKE TEST ]--
+
+// Smoke test timing: each entry has smokeTicks*smokeTick ms to get a size.
+const (
+	smokeTick  = 50
+	smokeTicks = 20
+)
+
+// smokeKnown lists entries with known problems, reported without failing.
+var smokeKnown = map[string]string{
+	"WindowControls": "don't show, see README",
+}
+
+// errSmokeSkip reports the entries needing a newer GTK, not checked.
+var errSmokeSkip = errors.New("skipped")
+
+// runSmoke checks every gallery entry with smokeEntries. Results are printed,
+// failures named by group and entry, and the app exits with code 1 if any
+// failed.
+//
+// Run it with a display, or headless on a virtual one, like xvfb-run. The same
+// checks run as tests, see smoke_test.go.
+func runSmoke() gtk.Widgetter {
+	checked, failed := 0, 0
+	report := func(group, name string, err error) {
+		switch {
+		case errors.Is(err, errSmokeSkip):
+			fmt.Printf("skip  %s/%s: %v\n", group, name, err)
+			return
+		case err == nil:
+			fmt.Printf("ok    %s/%s\n", group, name)
+		case smokeKnown[name] != "":
+			fmt.Printf("known %s/%s: %v (%s)\n", group, name, err, smokeKnown[name])
+		default:
+			fmt.Printf("FAIL  %s/%s: %v\n", group, name, err)
+			failed++
+		}
+		checked++
+	}
+	return smokeEntries(report, func() {
+		fmt.Printf("smoke: %d entries, %d failed\n", checked, failed)
+		if failed > 0 {
+			gapp.Exit(1)
+		} else {
+			gapp.Exit(0)
+		}
+	})
+}
+
+// smokeEntries adds every gallery entry to the returned box, one at a time,
+// and checks it's created without panic, realized with a size, and without GTK
+// critical log. Each entry result is reported, with a nil error when ok, then
+// done is called. Entries needing a newer GTK are reported with errSmokeSkip.
+//
+// Widgets with a timer, like the CustomWidget, must also stop it when removed,
+// and leave no goroutine.
+func smokeEntries(report func(group, name string, err error), done func()) gtk.Widgetter {
+	countCriticals()
+
+	type entry struct {
+		group, name string
+		maker       func() gtk.Widgetter
+	}
+	var list []entry
+	running := gtkVersion()
+	for _, group := range groups {
+		for _, item := range group.List {
+			if !item.Supported(running) {
+				report(group.Title, item.Name, fmt.Errorf("%w: requires GTK %s", errSmokeSkip, item.MinGTK))
+				continue
+			}
+			list = append(list, entry{group.Title, item.Name, item.Make})
+		}
+	}
+
+	box := gtknew.VBox(boxMargin)
+	var test func(i int)
+	test = func(i int) {
+		if i == len(list) {
+			done()
+			return
+		}
+
+		e := list[i]
+		criticals := criticalsCount()
+		goroutines := runtime.NumGoroutine()
+		w, err := smokeMake(e.maker)
+		if err != nil {
+			report(e.group, e.name, err)
+			externglib.IdleAdd(func() { test(i + 1) })
+			return
+		}
+		box.Append(w)
+
+		ticks := 0
+		externglib.TimeoutAdd(smokeTick, func() bool {
+			ticks++
+			sized := w.Realized() && w.AllocatedWidth() > 0 && w.AllocatedHeight() > 0
+			if !sized && ticks < smokeTicks {
+				return true // Wait for the next frame.
+			}
+			switch {
+			case !w.Realized():
+				err = errors.New("not realized")
+			case !sized:
+				err = fmt.Errorf("no size: %dx%d", w.AllocatedWidth(), w.AllocatedHeight())
+			case criticalsCount() != criticals:
+				err = fmt.Errorf("%d GTK critical", criticalsCount()-criticals)
+			}
+			box.Remove(w)
+
+			// Widgets with a timer must stop it when removed, and leave no goroutine.
+			if timer, ok := w.(interface{ Running() bool }); ok && err == nil {
+				switch {
+				case timer.Running():
+					err = errors.New("timer still running when unmapped")
+				case runtime.NumGoroutine() > goroutines:
+					err = fmt.Errorf("%d goroutines left", runtime.NumGoroutine()-goroutines)
+				}
+			}
+			report(e.group, e.name, err)
+			test(i + 1)
+			return false
+		})
+	}
+
+	externglib.IdleAdd(func() { test(0) })
+	return box
+}
+
+// countCriticals starts counting the GLib critical messages, still printed.
+func countCriticals() { C.gallery_count_criticals() }
+
+// criticalsCount returns the number of GLib critical messages, since counted.
+func criticalsCount() int { return int(C.gallery_criticals_count()) }
+
+// smokeMake creates the entry widget, catching panics.
+func smokeMake(maker func() gtk.Widgetter) (w gtk.Widgetter, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	w = maker()
+	if w == nil {
+		err = errors.New("nil widget")
+	}
+	return w, err
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	"github.com/gtkool4/grun"
+)
+
+// TestSmoke runs the --smoke checks on every gallery entry: created without
+// panic, realized with a size, and without GTK critical log. Failures are
+// named by group and entry, like TestSmoke/Displays/Scale.
+//
+// It needs a display, or a headless one:
+//
+//	xvfb-run go test -run Smoke
+//	GDK_BACKEND=broadway go test -run Smoke  # With broadwayd running.
+func TestSmoke(t *testing.T) {
+	requireDisplay(t)
+
+	results := make(map[string]error)
+	record := func(group, name string, err error) { results[group+"/"+name] = err }
+
+	flags := gapp.Flags
+	t.Cleanup(func() { gapp.Flags = flags })
+	gapp.Flags |= gio.ApplicationNonUnique // Don't activate a running gallery.
+	var e error
+	onMain(func() {
+		gapp.Init(func(*gtk.Application) {
+			e = grun.Exec(func() gtk.Widgetter {
+				return smokeEntries(record, func() { gapp.Exit(0) })
+			})(gapp)
+		})
+		gapp.App.Run([]string{os.Args[0]})
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	for _, group := range groups {
+		group := group // We're in a loop, so we need to make a static copy for the callback.
+		t.Run(group.Title, func(t *testing.T) {
+			for _, item := range group.List {
+				item := item // We're in a loop, so we need to make a static copy for the callback.
+				t.Run(item.Name, func(t *testing.T) {
+					err, ok := results[group.Title+"/"+item.Name]
+					switch {
+					case !ok:
+						t.Fatal("not checked")
+					case errors.Is(err, errSmokeSkip):
+						t.Skip(err)
+					case err != nil && smokeKnown[item.Name] != "":
+						t.Skipf("known: %v (%s)", err, smokeKnown[item.Name])
+					case err != nil:
+						t.Fatal(err)
+					}
+				})
+			}
+		})
+	}
+}
+
+// GTK must be used from the thread it was initialized on: TestMain keeps the
+// main thread, locked in init, and runs there the calls of the tests sent to
+// onMain. The tests themselves run in their own goroutines.
+var mainCalls = make(chan func())
+
+// hasDisplay is set by TestMain when GTK opened the display.
+var hasDisplay bool
+
+func init() { runtime.LockOSThread() }
+
+// TestMain initializes GTK once on the main thread, and runs the onMain calls
+// there until the tests are done.
+func TestMain(m *testing.M) {
+	if os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("GDK_BACKEND") == "broadway" {
+		hasDisplay = gtk.InitCheck()
+	}
+	done := make(chan int)
+	go func() { done <- m.Run() }()
+	for {
+		select {
+		case call := <-mainCalls:
+			call()
+		case code := <-done:
+			os.Exit(code)
+		}
+	}
+}
+
+// onMain runs the call on the main thread, and waits for it. The call must not
+// use t.Fatal or t.Skip: it would stop the main thread loop.
+func onMain(call func()) {
+	done := make(chan struct{})
+	mainCalls <- func() {
+		defer close(done)
+		call()
+	}
+	<-done
+}
+
+// requireDisplay skips the test without display.
+func requireDisplay(t *testing.T) {
+	if !hasDisplay {
+		t.Skip("no display: run with xvfb-run, or GDK_BACKEND=broadway and broadwayd")
+	}
+}
+
+// iterateUntil runs the GLib main loop until done returns true, or the delay
+// expires. It returns the last done result. Both run on the main thread.
+func iterateUntil(delay time.Duration, done func() bool) bool {
+	for end := time.Now().Add(delay); ; {
+		var ok, busy bool
+		onMain(func() {
+			if ok = done(); !ok {
+				busy = glib.MainContextDefault().Iteration(false)
+			}
+		})
+		switch {
+		case ok:
+			return true
+		case time.Now().After(end):
+			return false
+		case !busy:
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
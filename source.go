@@ -0,0 +1,180 @@
+package main
+
+import (
+	"embed"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+
+	externglib "github.com/diamondburned/gotk4/pkg/core/glib"
+
+	"github.com/gtkool4/gallery/registry"
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//------------------------------------------------------------------[ SOURCE ]--
+
+// sources embeds the gallery code, so the displayed examples always match the
+// running binary.
+//
+//go:embed *.go
+var sources embed.FS
+
+// funcSources maps function names to their source code, parsed on first use.
+var funcSources map[string]string
+
+// FuncName returns the name of a package function, like "newLabel".
+func FuncName(call interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(call).Pointer()).Name()
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// FuncSource returns the source code of the named function, with its doc comment.
+func FuncSource(name string) (string, bool) {
+	if funcSources == nil {
+		funcSources = parseSources(sources)
+	}
+	src, ok := funcSources[name]
+	return src, ok
+}
+
+func parseSources(fsys fs.FS) map[string]string {
+	list := make(map[string]string)
+	names, _ := fs.Glob(fsys, "*.go")
+	for _, name := range names {
+		byts, e := fs.ReadFile(fsys, name)
+		if e != nil {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, e := parser.ParseFile(fset, name, byts, parser.ParseComments)
+		if e != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil { // Only package functions can be makers.
+				continue
+			}
+			start := fn.Pos()
+			if fn.Doc != nil {
+				start = fn.Doc.Pos()
+			}
+			list[fn.Name.Name] = string(byts[fset.Position(start).Offset:fset.Position(fn.End()).Offset])
+		}
+	}
+	return list
+}
+
+//
+//-------------------------------------------------------------[ SOURCE VIEW ]--
+
+// Syntax highlight tags and their foreground color.
+var sourceTags = map[string]string{
+	"keyword": "#a626a4",
+	"string":  "#50a14f",
+	"comment": "#a0a1a7",
+	"number":  "#986801",
+}
+
+// EntrySource returns the code of the entry: its registered source, or the
+// source of its maker when it's a gallery function.
+func EntrySource(item registry.Entry) string {
+	if item.Source != "" {
+		return item.Source
+	}
+	name := FuncName(item.Make)
+	src, ok := FuncSource(name)
+	if !ok {
+		return "// source not found for " + name
+	}
+	return src
+}
+
+// newSourceView creates a read-only view of the entry source code, with a copy
+// to clipboard button.
+func newSourceView(item registry.Entry) gtk.Widgetter {
+	src := EntrySource(item)
+
+	tv := gtk.NewTextView()
+	tv.SetEditable(false)
+	tv.SetCursorVisible(false)
+	tv.SetMonospace(true)
+	highlightGo(tv.Buffer(), src)
+
+	scroll := gtknew.ScrolledWindow(tv)
+	scroll.SetSizeRequest(360, 200)
+	scroll.SetHasFrame(true)
+
+	btn := gtk.NewButtonFromIconName("edit-copy")
+	btn.SetTooltipText("Copy to clipboard")
+	btn.SetHAlign(gtk.AlignEnd)
+	btn.Connect("clicked", func() { btn.Clipboard().Set(externglib.NewValue(src)) })
+
+	return gtknew.VBox(boxMargin, scroll, btn)
+}
+
+// highlightGo sets the Go source text in the buffer with syntax colors.
+func highlightGo(buffer *gtk.TextBuffer, src string) {
+	buffer.SetText(src, -1)
+	for name, color := range sourceTags {
+		tag := gtk.NewTextTag(name)
+		tag.SetObjectProperty("foreground", color)
+		buffer.TagTable().Add(tag)
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var scan scanner.Scanner
+	scan.Init(file, []byte(src), nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := scan.Scan()
+		if tok == token.EOF {
+			return
+		}
+		name := ""
+		switch {
+		case tok.IsKeyword():
+			name = "keyword"
+		case tok == token.STRING || tok == token.CHAR:
+			name = "string"
+		case tok == token.COMMENT:
+			name = "comment"
+		case tok == token.INT || tok == token.FLOAT:
+			name = "number"
+		default:
+			continue
+		}
+		if lit == "" {
+			lit = tok.String()
+		}
+		offset := file.Offset(pos)
+		start := buffer.IterAtOffset(utf8.RuneCountInString(src[:offset]))
+		end := buffer.IterAtOffset(utf8.RuneCountInString(src[:offset+len(lit)]))
+		buffer.ApplyTagByName(name, &start, &end)
+	}
+}
+
+// newCodeToggle creates a "Show code" button revealing the entry source,
+// created on first use.
+func newCodeToggle(item registry.Entry) (toggle, revealer gtk.Widgetter) {
+	btn := gtk.NewToggleButtonWithLabel("Show code")
+	btn.SetHAlign(gtk.AlignEnd)
+	reveal := gtk.NewRevealer()
+	btn.Connect("toggled", func() {
+		if btn.Active() && reveal.Child() == nil {
+			reveal.SetChild(newSourceView(item))
+		}
+		reveal.SetRevealChild(btn.Active())
+	})
+	return btn, reveal
+}
@@ -0,0 +1,101 @@
+package main
+
+// #cgo pkg-config: glib-2.0
+// #include <glib.h>
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+	"github.com/diamondburned/gotk4/pkg/pango"
+
+	"github.com/gtkool4/gallery/registry"
+	"github.com/gtkool4/gtkelp/gtknew"
+)
+
+//
+//-----------------------------------------------------------------[ VERSION ]--
+
+// gtkVersion returns the running GTK version, compared to the entries MinGTK.
+func gtkVersion() registry.Version {
+	return registry.Version{
+		Major: int(gtk.GetMajorVersion()),
+		Minor: int(gtk.GetMinorVersion()),
+		Micro: int(gtk.GetMicroVersion()),
+	}
+}
+
+// libraryVersions lists the running libraries versions, and the GTK version
+// of the bindings.
+func libraryVersions() [][2]string {
+	return [][2]string{
+		{"GTK", gtkVersion().String()},
+		{"GLib", fmt.Sprintf("%d.%d.%d", C.glib_major_version, C.glib_minor_version, C.glib_micro_version)},
+		{"Pango", pango.VersionString()},
+		{"Go", runtime.Version()},
+		{"gotk4 bindings", fmt.Sprintf("GTK %d.%d.%d", gtk.MAJOR_VERSION, gtk.MINOR_VERSION, gtk.MICRO_VERSION)},
+	}
+}
+
+// versionsText formats the library versions, for the about dialog.
+func versionsText() string {
+	var lines []string
+	for _, lib := range libraryVersions() {
+		lines = append(lines, lib[0]+" "+lib[1])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// newVersionCard replaces the widget of an entry needing a newer GTK.
+func newVersionCard(item registry.Entry) gtk.Widgetter {
+	icon := gtk.NewImageFromIconName("dialog-information-symbolic")
+	icon.SetPixelSize(32)
+	text := gtknew.LabelWithMarkup(fmt.Sprintf("<b>Requires GTK %s</b>\n<small>Running GTK %s, see the Info page.</small>",
+		item.MinGTK, gtkVersion()))
+	text.SetJustify(gtk.JustifyCenter)
+	card := gtknew.VBox(boxMargin, icon, text)
+	card.AddCSSClass("card")
+	card.SetTooltipText(item.Description)
+	return card
+}
+
+// newInfoPage shows the running libraries versions, and the entries needing a
+// newer GTK.
+func newInfoPage() gtk.Widgetter {
+	grid := gtk.NewGrid()
+	grid.SetColumnSpacing(boxMargin * 2)
+	grid.SetRowSpacing(boxMargin)
+	for i, lib := range libraryVersions() {
+		name := gtknew.LabelWithMarkup("<b>" + lib[0] + "</b>")
+		name.SetHAlign(gtk.AlignStart)
+		value := gtk.NewLabel(lib[1])
+		value.SetHAlign(gtk.AlignStart)
+		value.SetSelectable(true)
+		grid.Attach(name, 0, i, 1, 1)
+		grid.Attach(value, 1, i, 1, 1)
+	}
+
+	running := gtkVersion()
+	var missing []string
+	for _, group := range groups {
+		for _, item := range group.List {
+			if !item.Supported(running) {
+				missing = append(missing, fmt.Sprintf("%s/%s: requires GTK %s", group.Title, item.Name, item.MinGTK))
+			}
+		}
+	}
+	status := "All entries are supported by this GTK version."
+	if len(missing) > 0 {
+		status = "Entries needing a newer GTK:\n" + strings.Join(missing, "\n")
+	}
+	label := gtk.NewLabel(status)
+	label.SetHAlign(gtk.AlignStart)
+
+	return gtknew.ScrolledWindow(gtknew.VBox(boxMargin,
+		gtknew.Frame("Versions", grid),
+		gtknew.Frame("Entries", label),
+	))
+}